@@ -20,6 +20,12 @@
 //   - Update the wasmImports map in .html files to include the short-sha in
 //     wasm imports. The wasmImports allows for cache busting hashed filenames
 //     for wasm files. The replacements are of the same form as the importmap.
+//   - With --integrity, add integrity and crossorigin attributes to rewritten
+//     <script src> and <link rel="stylesheet" href> tags, and emit companion
+//     <script type="application/json" id="asset-integrity-importmap"> and
+//     id="asset-integrity-wasm"> blocks alongside the importmap/wasmImports
+//     sections mapping each cache-busted filename to its Subresource
+//     Integrity digest.
 //
 // The site generation process copies the source hierarchy to a destination
 // directory and performs these updates as it copies the files.
@@ -28,7 +34,10 @@ package main
 import (
 	"bufio"
 	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -44,17 +53,26 @@ import (
 
 type app struct {
 	CacheBust bool   `help:"Rename .css, .js, and .wasm files to include short hash"`
+	Integrity bool   `help:"Emit Subresource Integrity (integrity/crossorigin) attributes for cache-busted assets. Requires --cache-bust."`
 	Domain    string `help:"Rewrite top-level paths to subdomains"`
 	SrcDir    string `arg:"" required:""`
 	DestDir   string `arg:"" required:""`
 
 	skippedFiles []string
-	renamedFiles map[string]string
+	renamedFiles map[string]renamedFile
+}
+
+// renamedFile records, for a cache-busted asset, the hashed name it was
+// copied to and the SHA-384 digest `updateHTMLFile` uses to populate
+// integrity attributes when --integrity is set.
+type renamedFile struct {
+	Name      string
+	Integrity string // "sha384-<base64 digest>", empty unless --integrity is set
 }
 
 func main() {
 	kctx := kong.Parse(&app{
-		renamedFiles: make(map[string]string),
+		renamedFiles: make(map[string]renamedFile),
 	})
 	kctx.FatalIfErrorf(kctx.Run())
 }
@@ -147,7 +165,7 @@ func (a *app) handleFile(filename string) error {
 		return nil
 	}
 	if a.CacheBust && (ext == ".js" || ext == ".css" || ext == ".wasm") {
-		shortSha, err := hashFile(srcfile)
+		shortSha, integrity, err := hashFile(srcfile)
 		if err != nil {
 			return err
 		}
@@ -157,7 +175,11 @@ func (a *app) handleFile(filename string) error {
 			//nolint:goerr113 // dynamic errors in package main is ok
 			return fmt.Errorf("duplicate filename: %s", srcfile)
 		}
-		a.renamedFiles[filename] = target
+		rf := renamedFile{Name: target}
+		if a.Integrity {
+			rf.Integrity = integrity
+		}
+		a.renamedFiles[filename] = rf
 		if ext == ".js" {
 			// also keep original JS filename for those who cannot use an `importmap` (e.g. ios 16.2)
 			if err := copyFile(srcfile, destfile); err != nil {
@@ -188,21 +210,26 @@ func (a *app) copyHTMLFiles() error {
 	return nil
 }
 
-// hashFile returns a short hash of the contents of filename. The short hash is
-// 32 bits, or 8 chars[0-9a-f] and with 100 file changes in a year (cache
-// expiry is one year) has a collision probability of less than 0.0000000005%.
-func hashFile(filename string) (string, error) {
+// hashFile returns a short hash of the contents of filename, for cache
+// busting, and a "sha384-<base64>" Subresource Integrity digest, for the
+// `integrity` attribute on the renamed file's script/link tag. The short
+// hash is 32 bits, or 8 chars[0-9a-f] and with 100 file changes in a year
+// (cache expiry is one year) has a collision probability of less than
+// 0.0000000005%.
+func hashFile(filename string) (shortSha, integrity string, err error) {
 	f, err := os.Open(filename)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 	defer f.Close() //nolint:errcheck // don't care about close failing on read-only files
-	h := sha256.New()
-	if _, err := io.Copy(h, f); err != nil {
-		return "", err
+	sha256Hash := sha256.New()
+	sha384Hash := sha512.New384()
+	if _, err := io.Copy(io.MultiWriter(sha256Hash, sha384Hash), f); err != nil {
+		return "", "", err
 	}
-	sha := h.Sum(nil)
-	return hex.EncodeToString(sha[:4]), nil
+	shortSha = hex.EncodeToString(sha256Hash.Sum(nil)[:4])
+	integrity = "sha384-" + base64.StdEncoding.EncodeToString(sha384Hash.Sum(nil))
+	return shortSha, integrity, nil
 }
 
 func openInOut(src, dest string) (io.ReadCloser, io.WriteCloser, error) {
@@ -254,6 +281,8 @@ var (
 func (a *app) updateHTMLFile(w io.Writer, r io.Reader, filename string) error {
 	inImportmap := false
 	inWASMImports := false
+	importIntegrity := map[string]string{}
+	wasmIntegrity := map[string]string{}
 	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		line := scanner.Text()
@@ -269,22 +298,33 @@ func (a *app) updateHTMLFile(w io.Writer, r io.Reader, filename string) error {
 			if strings.Contains(line, `<script type="importmap">`) {
 				inImportmap = true
 			}
-			if inImportmap && strings.Contains(line, `</script>`) {
-				inImportmap = false
-			}
 			if strings.Contains(line, `const wasmImports = {`) {
 				inWASMImports = true
 			}
-			if inWASMImports && strings.Contains(line, `</script>`) {
-				inWASMImports = false
-			}
 
-			line = updateRefs(filename, line, a.renamedFiles)
+			line = updateRefs(filename, line, a.renamedFiles, a.Integrity)
 			if inImportmap {
-				line = updateImportMap(filename, line, a.renamedFiles)
+				line = updateImportMap(filename, line, a.renamedFiles, importIntegrity)
 			}
 			if inWASMImports {
-				line = updateWASMImports(filename, line, a.renamedFiles)
+				line = updateWASMImports(filename, line, a.renamedFiles, wasmIntegrity)
+			}
+
+			if inImportmap && strings.Contains(line, `</script>`) {
+				inImportmap = false
+				block, err := integrityBlock("asset-integrity-importmap", importIntegrity, a.Integrity)
+				if err != nil {
+					return err
+				}
+				line += block
+			}
+			if inWASMImports && strings.Contains(line, `</script>`) {
+				inWASMImports = false
+				block, err := integrityBlock("asset-integrity-wasm", wasmIntegrity, a.Integrity)
+				if err != nil {
+					return err
+				}
+				line += block
 			}
 		}
 
@@ -296,54 +336,106 @@ func (a *app) updateHTMLFile(w io.Writer, r io.Reader, filename string) error {
 	return scanner.Err()
 }
 
-func updateRefs(filename, line string, renamedFiles map[string]string) string {
+func updateRefs(filename, line string, renamedFiles map[string]renamedFile, withIntegrity bool) string {
 	// Rewrite .js and .css in href and src attributes
-	if matches := jscssRefRE.FindStringSubmatch(line); len(matches) > 0 {
-		newname := getNewName(filename, matches[2], renamedFiles)
-		if newname != "" {
-			replacement := `$1="` + newname + `"`
-			line = jscssRefRE.ReplaceAllString(line, replacement)
-		}
+	matches := jscssRefRE.FindStringSubmatch(line)
+	if len(matches) == 0 {
+		return line
+	}
+	rf, ok := getRenamedFile(filename, matches[2], renamedFiles)
+	if !ok {
+		return line
+	}
+	replacement := `$1="` + rf.Name + `"`
+	line = jscssRefRE.ReplaceAllString(line, replacement)
+	if withIntegrity && rf.Integrity != "" {
+		line = addIntegrityAttrs(line, rf.Integrity)
 	}
 	return line
 }
 
-func updateImportMap(filename, line string, renamedFiles map[string]string) string {
+// addIntegrityAttrs injects integrity and crossorigin attributes into a
+// <script src="…"> or <link rel="stylesheet" href="…"> tag, assuming (as
+// the rest of this file's line-based rewriting already does) that the
+// whole tag is on one line.
+func addIntegrityAttrs(line, integrity string) string {
+	if !strings.Contains(line, "<script") && !strings.Contains(line, "<link") {
+		return line
+	}
+	if strings.Contains(line, "integrity=") {
+		return line
+	}
+	idx := strings.Index(line, ">")
+	if idx == -1 {
+		return line
+	}
+	attrs := ` integrity="` + integrity + `" crossorigin="anonymous"`
+	return line[:idx] + attrs + line[idx:]
+}
+
+func updateImportMap(filename, line string, renamedFiles map[string]renamedFile, integrity map[string]string) string {
 	// Rewrite .js filenames in importmap
 	if matches := importmapRE.FindStringSubmatch(line); len(matches) > 0 {
-		newname := getNewName(filename, matches[2], renamedFiles)
-		if newname != "" {
-			replacement := `"$1": "./` + newname + `"`
+		rf, ok := getRenamedFile(filename, matches[2], renamedFiles)
+		if ok {
+			replacement := `"$1": "./` + rf.Name + `"`
 			line = importmapRE.ReplaceAllString(line, replacement)
+			if rf.Integrity != "" {
+				integrity[rf.Name] = rf.Integrity
+			}
 		}
 	}
 	return line
 }
 
-func updateWASMImports(filename, line string, renamedFiles map[string]string) string {
+func updateWASMImports(filename, line string, renamedFiles map[string]renamedFile, integrity map[string]string) string {
 	// Rewrite .wasm filenames in wasm map
 	if matches := wasmmapRE.FindStringSubmatch(line); len(matches) > 0 {
-		newname := getNewName(filename, matches[2], renamedFiles)
-		if newname != "" {
-			replacement := `"$1": "./` + newname + `"`
+		rf, ok := getRenamedFile(filename, matches[2], renamedFiles)
+		if ok {
+			replacement := `"$1": "./` + rf.Name + `"`
 			line = wasmmapRE.ReplaceAllString(line, replacement)
+			if rf.Integrity != "" {
+				integrity[rf.Name] = rf.Integrity
+			}
 		}
 	}
 	return line
 }
 
-// getNewName returns the filename in `match` that appeared in `filename` as a
-// renamed filename if it appears in `renamedFiles`. e.g. If the file
-// `./play/index.html` contained a match of `../css/fonts.css` and the file
-// `./css/fonts.css` was renamed to `fonts.12345678.css`, getNewName will
-// return `../css/fonts.12345678.css`. If the file referenced by `match` was
-// not renamed, an empty string is returned.
-func getNewName(filename, match string, renamedFiles map[string]string) string {
+// integrityBlock returns a companion
+// <script type="application/json" id="..."> block, identified by id and
+// preceded by a newline, for appending to the line that closes an
+// importmap or wasmImports block. The runtime loader can consult it
+// before WebAssembly.instantiateStreaming or a dynamic import; it maps
+// each cache-busted filename encountered in the preceding importmap or
+// wasmImports block to its integrity digest. It returns "" when disabled
+// or when there is nothing to map. Callers must pass a distinct id per
+// block so both can be looked up by getElementById.
+func integrityBlock(id string, integrity map[string]string, enabled bool) (string, error) {
+	if !enabled || len(integrity) == 0 {
+		return "", nil
+	}
+	b, err := json.Marshal(integrity)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("\n<script type=\"application/json\" id=\"%s\">%s</script>", id, b), nil
+}
+
+// getRenamedFile returns the renamedFile that `match`, as it appeared in
+// `filename`, was renamed to, if any. e.g. If the file `./play/index.html`
+// contained a match of `../css/fonts.css` and the file `./css/fonts.css`
+// was renamed to `fonts.12345678.css`, the returned renamedFile.Name is
+// `../css/fonts.12345678.css`. ok is false if the file referenced by
+// `match` was not renamed.
+func getRenamedFile(filename, match string, renamedFiles map[string]renamedFile) (renamedFile, bool) {
 	src := filepath.Join(filepath.Dir(filename), filepath.FromSlash(match))
 	target := filepath.Clean(src)
-	hashedName, ok := renamedFiles[target]
+	rf, ok := renamedFiles[target]
 	if !ok {
-		return ""
+		return renamedFile{}, false
 	}
-	return path.Join(path.Dir(match), hashedName)
+	rf.Name = path.Join(path.Dir(match), rf.Name)
+	return rf, true
 }
@@ -0,0 +1,359 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"evylang.dev/evy/learn/pkg/question"
+)
+
+// hostCmd runs a self-contained, MOTH-style scoring server for a course
+// tree: participants submit answers over HTTP and are scored without
+// ever being sent the answer itself.
+type hostCmd struct {
+	CourseDir  string `arg:"" type:"path" help:"Root directory of question markdown files to host." placeholder:"COURSE-DIR"`
+	Addr       string `default:"localhost:8081" help:"Address to listen on."`
+	PrivateKey string `short:"k" help:"Secret private key to decrypt sealed answers for grading." env:"EVY_LEARN_PRIVATE_KEY"`
+	SubmitLog  string `default:"submissions.log" type:"path" help:"Append-only log of submissions, so restarts don't lose scores."`
+}
+
+func (c *hostCmd) Run() error {
+	srv, err := newGradingServer(c.CourseDir, c.PrivateKey, c.SubmitLog)
+	if err != nil {
+		return err
+	}
+	defer srv.logFile.Close()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /answer", srv.handleAnswer)
+	mux.HandleFunc("GET /puzzles.json", srv.handlePuzzles)
+	mux.HandleFunc("GET /points.json", srv.handlePoints)
+	mux.HandleFunc("GET /q/{id...}", srv.handleQuestion)
+
+	fmt.Printf("learnevy host: listening on http://%s (course %s)\n", c.Addr, c.CourseDir)
+	return http.ListenAndServe(c.Addr, mux)
+}
+
+// puzzle is a hosted question plus the scoring metadata derived from its
+// frontmatter difficulty.
+type puzzle struct {
+	md         *question.Markdown
+	id         string
+	difficulty string
+	points     int
+	retriable  bool
+}
+
+// submission is one append-only log entry, also used as the in-memory
+// record replayed on startup to reconstruct scores.
+type submission struct {
+	Time        string `json:"time"`
+	Participant string `json:"participant"`
+	QuestionID  string `json:"question_id"`
+	Correct     bool   `json:"correct"`
+}
+
+// gradingServer holds the hosted puzzle set and in-memory scoreboard,
+// kept consistent with the on-disk submission log.
+type gradingServer struct {
+	privateKey string
+	puzzles    map[string]*puzzle
+
+	logFile *os.File
+	logMu   sync.Mutex
+
+	scoreMu  sync.Mutex
+	solved   map[string]map[string]bool // participant -> question id -> solved
+	attempts map[string]map[string]int  // participant -> question id -> attempt count
+
+	submitMu    sync.Mutex
+	submitLocks map[string]*sync.Mutex // "participant\x00question id" -> lock
+}
+
+func newGradingServer(courseDir, privateKey, logPath string) (*gradingServer, error) {
+	puzzles, err := loadPuzzles(courseDir)
+	if err != nil {
+		return nil, err
+	}
+	logFile, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0o666)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open submission log: %w", err)
+	}
+	srv := &gradingServer{
+		privateKey:  privateKey,
+		puzzles:     puzzles,
+		logFile:     logFile,
+		solved:      map[string]map[string]bool{},
+		attempts:    map[string]map[string]int{},
+		submitLocks: map[string]*sync.Mutex{},
+	}
+	if err := srv.replayLog(); err != nil {
+		return nil, err
+	}
+	return srv, nil
+}
+
+// loadPuzzles walks courseDir for question markdown files. Files that
+// aren't questions (course/unit/exercise indexes) fail question.NewMarkdown
+// and are silently skipped, as is any other file NewMarkdown rejects.
+func loadPuzzles(courseDir string) (map[string]*puzzle, error) {
+	puzzles := map[string]*puzzle{}
+	err := filepath.Walk(courseDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".md") {
+			return nil
+		}
+		md, err := question.NewMarkdown(path)
+		if err != nil {
+			return nil
+		}
+		id := questionID(courseDir, path)
+		puzzles[id] = &puzzle{
+			md:         md,
+			id:         id,
+			difficulty: md.Difficulty(),
+			points:     md.Points(),
+			retriable:  md.Retriable(),
+		}
+		return nil
+	})
+	return puzzles, err
+}
+
+func questionID(courseDir, path string) string {
+	rel, err := filepath.Rel(courseDir, path)
+	if err != nil {
+		rel = path
+	}
+	rel = strings.TrimSuffix(rel, filepath.Ext(rel))
+	return filepath.ToSlash(rel)
+}
+
+// replayLog reconstructs the in-memory scoreboard from every previously
+// logged submission, so a restart doesn't lose participant scores.
+func (s *gradingServer) replayLog() error {
+	if _, err := s.logFile.Seek(0, 0); err != nil {
+		return err
+	}
+	scanner := bufio.NewScanner(s.logFile)
+	for scanner.Scan() {
+		var sub submission
+		if err := json.Unmarshal(scanner.Bytes(), &sub); err != nil {
+			continue // ignore a truncated trailing line from a prior crash
+		}
+		if sub.Correct {
+			s.markSolved(sub.Participant, sub.QuestionID)
+		}
+		s.incrementAttempts(sub.Participant, sub.QuestionID)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	_, err := s.logFile.Seek(0, io.SeekEnd)
+	return err
+}
+
+type answerRequest struct {
+	Participant string `json:"participant"`
+	QuestionID  string `json:"question-id"`
+	Submission  string `json:"submission"`
+}
+
+type answerResponse struct {
+	Correct bool   `json:"correct"`
+	Message string `json:"message,omitempty"`
+}
+
+// handleAnswer grades a submission and records it, but never echoes the
+// answer itself back to the client.
+func (s *gradingServer) handleAnswer(w http.ResponseWriter, r *http.Request) {
+	var req answerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	if req.Participant == "" || req.QuestionID == "" {
+		http.Error(w, "participant and question-id are required", http.StatusBadRequest)
+		return
+	}
+	p, ok := s.puzzles[req.QuestionID]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	// Hold a per-(participant, question) lock across the solved/attempts
+	// check, grading and record, so two concurrent submissions for the
+	// same non-retriable question can't both observe zero attempts and
+	// both get graded.
+	submitMu := s.submitLock(req.Participant, req.QuestionID)
+	submitMu.Lock()
+	defer submitMu.Unlock()
+
+	s.scoreMu.Lock()
+	alreadySolved := s.isSolved(req.Participant, req.QuestionID)
+	attempts := s.attemptCount(req.Participant, req.QuestionID)
+	s.scoreMu.Unlock()
+
+	if alreadySolved {
+		writeJSON(w, answerResponse{Correct: true, Message: "already solved"})
+		return
+	}
+	if !p.retriable && attempts > 0 {
+		writeJSON(w, answerResponse{Correct: false, Message: "no attempts remaining"})
+		return
+	}
+
+	correct, err := p.md.CheckAnswer(s.privateKey, req.Submission)
+	if err != nil {
+		http.Error(w, "internal error grading submission", http.StatusInternalServerError)
+		return
+	}
+	if err := s.record(req.Participant, req.QuestionID, correct); err != nil {
+		http.Error(w, "internal error recording submission", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, answerResponse{Correct: correct})
+}
+
+// record appends the submission to the on-disk log before updating the
+// in-memory scoreboard, so the log is always at least as current as the
+// scoreboard a reader sees.
+func (s *gradingServer) record(participant, questionID string, correct bool) error {
+	sub := submission{
+		Time:        time.Now().UTC().Format(time.RFC3339),
+		Participant: participant,
+		QuestionID:  questionID,
+		Correct:     correct,
+	}
+	b, err := json.Marshal(sub)
+	if err != nil {
+		return err
+	}
+	s.logMu.Lock()
+	_, err = fmt.Fprintln(s.logFile, string(b))
+	s.logMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	s.scoreMu.Lock()
+	if correct {
+		s.markSolved(participant, questionID)
+	}
+	s.incrementAttempts(participant, questionID)
+	s.scoreMu.Unlock()
+	return nil
+}
+
+// submitLock returns the lock serializing submissions for a single
+// (participant, question) pair, creating it on first use.
+func (s *gradingServer) submitLock(participant, questionID string) *sync.Mutex {
+	key := participant + "\x00" + questionID
+	s.submitMu.Lock()
+	defer s.submitMu.Unlock()
+	mu, ok := s.submitLocks[key]
+	if !ok {
+		mu = &sync.Mutex{}
+		s.submitLocks[key] = mu
+	}
+	return mu
+}
+
+// The is*/mark*/increment* helpers below assume scoreMu is already held
+// by the caller.
+
+func (s *gradingServer) isSolved(participant, id string) bool {
+	return s.solved[participant][id]
+}
+
+func (s *gradingServer) markSolved(participant, id string) {
+	if s.solved[participant] == nil {
+		s.solved[participant] = map[string]bool{}
+	}
+	s.solved[participant][id] = true
+}
+
+func (s *gradingServer) attemptCount(participant, id string) int {
+	return s.attempts[participant][id]
+}
+
+func (s *gradingServer) incrementAttempts(participant, id string) {
+	if s.attempts[participant] == nil {
+		s.attempts[participant] = map[string]int{}
+	}
+	s.attempts[participant][id]++
+}
+
+type puzzleInfo struct {
+	ID         string `json:"id"`
+	Difficulty string `json:"difficulty"`
+	Points     int    `json:"points"`
+}
+
+func (s *gradingServer) handlePuzzles(w http.ResponseWriter, _ *http.Request) {
+	infos := make([]puzzleInfo, 0, len(s.puzzles))
+	for _, p := range s.puzzles {
+		infos = append(infos, puzzleInfo{ID: p.id, Difficulty: p.difficulty, Points: p.points})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].ID < infos[j].ID })
+	writeJSON(w, infos)
+}
+
+// handlePoints returns the scoreboard: total points earned per
+// participant, summed over their solved puzzles.
+func (s *gradingServer) handlePoints(w http.ResponseWriter, _ *http.Request) {
+	s.scoreMu.Lock()
+	scores := make(map[string]int, len(s.solved))
+	for participant, solvedIDs := range s.solved {
+		total := 0
+		for id := range solvedIDs {
+			if p, ok := s.puzzles[id]; ok {
+				total += p.points
+			}
+		}
+		scores[participant] = total
+	}
+	s.scoreMu.Unlock()
+	writeJSON(w, scores)
+}
+
+func (s *gradingServer) handleQuestion(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	p, ok := s.puzzles[id]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	stripped, err := p.md.Stripped()
+	if err != nil {
+		http.Error(w, "internal error rendering question", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+	fmt.Fprint(w, stripped)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
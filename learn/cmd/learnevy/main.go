@@ -1,6 +1,6 @@
 // Learnevy is a tool for creating Evy practice and learn materials.
 //
-// Learnevy has the following sub-commands: export, verify, serve, seal, unseal.
+// Learnevy has the following sub-commands: export, verify, verify-sig, serve, host, seal, unseal.
 //
 //	Usage: learnevy <command> [flags]
 //
@@ -17,6 +17,15 @@
 //	  verify <md-file> [<type>] [flags]
 //	    Verify encryptedAnsers in markdown file. Ensure no plaintext answers.
 //
+//	  verify-sig <answer-key-file> [flags]
+//	    Verify the detached signature of an exported answer key.
+//
+//	  serve <root> [flags]
+//	    Serve a live preview of a course/unit/exercise/question markdown tree.
+//
+//	  host <course-dir> [flags]
+//	    Host a MOTH-style scoring server for a course tree.
+//
 //	  seal <md-file> [flags]
 //	    Move 'answer' to 'sealed-answer' in source markdown.
 //
@@ -28,8 +37,11 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"evylang.dev/evy/learn/pkg/question"
 	"github.com/alecthomas/kong"
@@ -42,8 +54,11 @@ learnevy is a tool that manages practice and learn resources for Evy.
 var version = "v0.0.0"
 
 type app struct {
-	Export exportCmd `cmd:"" help:"Export answer key File."`
-	Verify verifyCmd `cmd:"" help:"Verify encryptedAnsers in markdown file. Ensure no plaintext answers."`
+	Export    exportCmd    `cmd:"" help:"Export answer key File."`
+	Verify    verifyCmd    `cmd:"" help:"Verify encryptedAnsers in markdown file. Ensure no plaintext answers."`
+	VerifySig verifySigCmd `cmd:"" help:"Verify the detached signature of an exported answer key."`
+	Serve     serveCmd     `cmd:"" help:"Serve a live preview of a course/unit/exercise/question markdown tree."`
+	Host      hostCmd      `cmd:"" help:"Host a MOTH-style scoring server for a course tree."`
 
 	Seal   sealCmd   `cmd:"" help:"Move 'answer' to 'sealed-answer' in source markdown."`
 	Unseal unsealCmd `cmd:"" help:"Move 'sealed-answer' to 'answer' in source markdown."`
@@ -57,6 +72,7 @@ type cryptoCmd struct {
 	Keygen keygenCryptoCmd `cmd:"" help:"Generate a new secret key."`
 	Seal   sealCryptoCmd   `cmd:"" help:"Encrypt a string given on command line"`
 	Unseal unsealCryptoCmd `cmd:"" help:"Decrypt string given on command line"`
+	Rekey  rekeyCryptoCmd  `cmd:"" help:"Re-seal every sealed-answer under a course tree with a new key."`
 }
 
 func main() {
@@ -69,7 +85,17 @@ func main() {
 }
 
 type keygenCryptoCmd struct {
-	Length int `short:"l" default:"2048" help:"Length of key to generate."`
+	Length int    `short:"l" default:"2048" help:"Length of key to generate. Ignored for --scheme=age,sign."`
+	Scheme string `short:"s" default:"rsa" enum:"rsa,age,sign" help:"Key scheme: rsa seals answers, age generates an X25519 keypair (which seal and unseal do not accept), sign (Ed25519) authenticates exported answer keys."`
+}
+
+type rekeyCryptoCmd struct {
+	From   string `enum:"rsa,age" required:"" help:"Scheme of the existing private key."`
+	To     string `enum:"rsa,age" required:"" help:"Scheme of the new public key."`
+	MDFile string `type:"path" required:"" help:"Root course/unit/exercise/question markdown file or directory to walk."`
+
+	FromPrivateKey string `short:"k" help:"Private key matching --from, to unseal existing answers." env:"EVY_LEARN_PRIVATE_KEY"`
+	ToPublicKey    string `short:"p" required:"" help:"Public key matching --to, to re-seal answers."`
 }
 type sealCryptoCmd struct {
 	Plaintext string `arg:"" help:"Plaintext to encrypt."`
@@ -80,7 +106,16 @@ type unsealCryptoCmd struct {
 }
 
 func (c *keygenCryptoCmd) Run() error {
-	keys, err := question.Keygen(c.Length)
+	var keys question.Keys
+	var err error
+	switch c.Scheme {
+	case "age":
+		keys, err = question.KeygenAge()
+	case "sign":
+		keys, err = question.KeygenSign()
+	default:
+		keys, err = question.Keygen(c.Length)
+	}
 	if err != nil {
 		return err
 	}
@@ -88,6 +123,35 @@ func (c *keygenCryptoCmd) Run() error {
 	return nil
 }
 
+func (c *rekeyCryptoCmd) Run() error {
+	return filepath.Walk(c.MDFile, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(path, ".md") {
+			return nil
+		}
+		md, err := question.NewMarkdown(path)
+		if errors.Is(err, question.ErrWrongFrontmatterType) || errors.Is(err, question.ErrNoFrontmatter) {
+			return nil // course, unit or exercise index: nothing to re-seal
+		}
+		if err != nil {
+			return err
+		}
+		if err := md.Unseal(c.FromPrivateKey); err != nil {
+			return fmt.Errorf("rekey %s: %w", path, err)
+		}
+		if err := md.Seal(c.ToPublicKey); err != nil {
+			return fmt.Errorf("rekey %s: %w", path, err)
+		}
+		formatted, err := md.Format()
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(path, []byte(formatted), 0o666)
+	})
+}
+
 func (c *sealCryptoCmd) Run() error {
 	encrypted, err := question.Encrypt(question.PublicKey, c.Plaintext)
 	if err != nil {
@@ -111,16 +175,29 @@ type exportCmd struct {
 	AnswerKeyFile string `arg:"" default:"-" help:"JSON output file for answer key (default: stdout)." placeholder:"ANSWERFILE"`
 	UnsealedOnly  bool   `short:"u" help:"Only export files with unsealed answers. Suitable if private key not available."`
 	PrivateKey    string `short:"k" help:"Secret private key to decrypt sealed answers." env:"EVY_LEARN_PRIVATE_KEY"`
+
+	Sign       bool   `help:"Also write a detached signature file, <answer-key-file>.sig. Requires --signing-key and an output file, not stdout."`
+	SigningKey string `help:"Ed25519 signing private key, distinct from the sealing key. Generate with 'crypto keygen --scheme=sign'." env:"EVY_LEARN_SIGNING_KEY"`
+}
+
+// verifySigCmd verifies the detached signature an exportCmd with --sign
+// wrote alongside an answer key, so the answer key can travel through an
+// untrusted channel (CDN, email) and still be authenticated on arrival.
+type verifySigCmd struct {
+	AnswerKeyFile string `arg:"" type:"existingfile" help:"Exported answer key JSON file; its signature is read from the sibling <file>.sig." placeholder:"ANSWERFILE"`
+	PublicKey     string `short:"k" required:"" help:"Ed25519 signing public key to verify against."`
 }
 
 type sealCmd struct {
-	MDFile    string `arg:"" type:"markdownfile" help:"Markdown file with course, unit, exercise, or question." placeholder:"ANSWERFILE"`
-	PublicKey string `short:"k" help:"public key to seal answers, default provided"`
+	MDFile    string   `arg:"" type:"markdownfile" help:"Markdown file with course, unit, exercise, or question." placeholder:"ANSWERFILE"`
+	PublicKey string   `short:"k" help:"public key to seal answers, default provided"`
+	Recipient []string `short:"r" help:"Recipient as id=publickey; repeatable. Overrides --public-key and produces a multi-recipient sealed-answer."`
 }
 
 type unsealCmd struct {
 	MDFile     string `arg:"" type:"markdownfile" help:"Markdown file with course, unit, exercise, or question." placeholder:"ANSWERFILE"`
 	PrivateKey string `short:"k" help:"Secret private key to decrypt sealed answers." env:"EVY_LEARN_PRIVATE_KEY"`
+	As         string `help:"Recipient id to unseal as, for a multi-recipient sealed-answer. Defaults to trying every recipient."`
 }
 
 type verifyCmd struct {
@@ -148,6 +225,11 @@ func (c *exportCmd) Run() error {
 	if err != nil {
 		return err
 	}
+	if c.Sign {
+		if err := c.writeSignature(b); err != nil {
+			return err
+		}
+	}
 	if c.AnswerKeyFile != "-" {
 		return os.WriteFile(c.AnswerKeyFile, append(b, '\n'), 0o666)
 	}
@@ -155,18 +237,64 @@ func (c *exportCmd) Run() error {
 	return nil
 }
 
-func (c *sealCmd) Run() error {
-	md, err := question.NewMarkdown(c.MDFile)
+func (c *exportCmd) writeSignature(answerKeyJSON []byte) error {
+	if c.AnswerKeyFile == "-" {
+		return fmt.Errorf("cannot sign an answer key written to stdout, give an output file")
+	}
+	if c.SigningKey == "" {
+		return fmt.Errorf("--sign requires --signing-key (or EVY_LEARN_SIGNING_KEY)")
+	}
+	sig, err := question.Sign(c.SigningKey, answerKeyJSON)
 	if err != nil {
 		return err
 	}
-	publicKey := c.PublicKey
-	if publicKey == "" {
-		publicKey = question.PublicKey
+	comment := fmt.Sprintf("verify with 'learnevy verify-sig %s -k <signing-public-key>'", c.AnswerKeyFile)
+	sigFile := question.FormatSignatureFile(comment, sig)
+	return os.WriteFile(c.AnswerKeyFile+".sig", []byte(sigFile), 0o666)
+}
+
+func (c *verifySigCmd) Run() error {
+	b, err := os.ReadFile(c.AnswerKeyFile)
+	if err != nil {
+		return err
 	}
-	if err := md.Seal(publicKey); err != nil {
+	sigContent, err := os.ReadFile(c.AnswerKeyFile + ".sig")
+	if err != nil {
+		return err
+	}
+	sig, err := question.ParseSignatureFile(string(sigContent))
+	if err != nil {
 		return err
 	}
+	if err := question.VerifySignature(c.PublicKey, b, sig); err != nil {
+		return fmt.Errorf("%s: %w", c.AnswerKeyFile, err)
+	}
+	fmt.Println("OK")
+	return nil
+}
+
+func (c *sealCmd) Run() error {
+	md, err := question.NewMarkdown(c.MDFile)
+	if err != nil {
+		return err
+	}
+	if len(c.Recipient) > 0 {
+		recipients, err := parseRecipients(c.Recipient)
+		if err != nil {
+			return err
+		}
+		if err := md.SealRecipients(recipients); err != nil {
+			return err
+		}
+	} else {
+		publicKey := c.PublicKey
+		if publicKey == "" {
+			publicKey = question.PublicKey
+		}
+		if err := md.Seal(publicKey); err != nil {
+			return err
+		}
+	}
 	formatted, err := md.Format()
 	if err != nil {
 		return err
@@ -179,7 +307,7 @@ func (c *unsealCmd) Run() error {
 	if err != nil {
 		return err
 	}
-	if err := md.Unseal(c.PrivateKey); err != nil {
+	if err := md.UnsealAs(c.As, c.PrivateKey); err != nil {
 		return err
 	}
 	formatted, err := md.Format()
@@ -189,6 +317,19 @@ func (c *unsealCmd) Run() error {
 	return os.WriteFile(c.MDFile, []byte(formatted), 0o666)
 }
 
+// parseRecipients parses "id=publickey" flag values into Recipients.
+func parseRecipients(raw []string) ([]question.Recipient, error) {
+	recipients := make([]question.Recipient, 0, len(raw))
+	for _, r := range raw {
+		id, pub, ok := strings.Cut(r, "=")
+		if !ok || id == "" || pub == "" {
+			return nil, fmt.Errorf("invalid --recipient %q, want id=publickey", r)
+		}
+		recipients = append(recipients, question.Recipient{ID: id, PublicKey: pub})
+	}
+	return recipients, nil
+}
+
 func (c *verifyCmd) Run() error {
 	md, err := question.NewMarkdown(c.MDFile)
 	if err != nil {
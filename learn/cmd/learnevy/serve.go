@@ -0,0 +1,285 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"evylang.dev/evy/learn/pkg/question"
+	"github.com/fsnotify/fsnotify"
+	"rsc.io/markdown"
+)
+
+// debounceDelay coalesces bursts of filesystem events, e.g. an editor that
+// writes a file in several steps on every save, into a single re-render.
+const debounceDelay = 100 * time.Millisecond
+
+type serveCmd struct {
+	Root       string `arg:"" type:"path" default:"." help:"Root of course/unit/exercise/question markdown tree to serve." placeholder:"ROOT"`
+	Addr       string `default:"localhost:8080" help:"Address to listen on."`
+	AnswerKey  bool   `help:"Render decrypted answers inline below each question, for local authoring."`
+	PrivateKey string `short:"k" help:"Secret private key to decrypt sealed answers, for --answer-key." env:"EVY_LEARN_PRIVATE_KEY"`
+}
+
+func (c *serveCmd) Run() error {
+	srv, err := newPreviewServer(c.Root, c.AnswerKey, c.PrivateKey)
+	if err != nil {
+		return err
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("cannot start filesystem watcher: %w", err)
+	}
+	defer watcher.Close()
+	if err := addTreeToWatcher(watcher, c.Root); err != nil {
+		return err
+	}
+	go srv.watch(watcher)
+
+	fmt.Printf("learnevy serve: listening on http://%s (root %s)\n", c.Addr, c.Root)
+	return http.ListenAndServe(c.Addr, srv.mux)
+}
+
+// previewServer renders a course/unit/exercise/question markdown tree to
+// HTML for local authoring. It re-renders individual files as their
+// source changes and pushes a reload event to connected browsers over
+// Server-Sent Events.
+type previewServer struct {
+	root       string
+	answerKey  bool
+	privateKey string
+	mux        *http.ServeMux
+
+	mu    sync.RWMutex
+	pages map[string]string // root-relative path -> rendered HTML
+
+	reloadMu sync.Mutex
+	reload   map[chan struct{}]bool
+}
+
+func newPreviewServer(root string, answerKey bool, privateKey string) (*previewServer, error) {
+	s := &previewServer{
+		root:       root,
+		answerKey:  answerKey,
+		privateKey: privateKey,
+		pages:      map[string]string{},
+		reload:     map[chan struct{}]bool{},
+	}
+	walkFn := func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".md") {
+			return nil
+		}
+		return s.renderAndCache(path)
+	}
+	if err := filepath.Walk(root, walkFn); err != nil {
+		return nil, err
+	}
+	s.mux = http.NewServeMux()
+	s.mux.HandleFunc("/events", s.handleEvents)
+	s.mux.HandleFunc("/", s.handlePage)
+	return s, nil
+}
+
+// renderFile renders a single question markdown file. Files whose
+// frontmatter type is not "question" (e.g. a course, unit or exercise
+// index) are rendered as plain markdown, since question.NewMarkdown
+// rejects any other frontmatter type.
+func (s *previewServer) renderFile(path string) (string, error) {
+	md, err := question.NewMarkdown(path)
+	switch {
+	case err == nil:
+		return s.renderQuestion(md)
+	case errors.Is(err, question.ErrWrongFrontmatterType), errors.Is(err, question.ErrNoFrontmatter):
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		return renderMarkdown(string(content)), nil
+	default:
+		return "", err
+	}
+}
+
+func (s *previewServer) renderQuestion(md *question.Markdown) (string, error) {
+	body := markdown.ToHTML(md.Doc)
+	if !s.answerKey {
+		return body, nil
+	}
+	if err := md.Unseal(s.privateKey); err != nil {
+		// Best-effort: still preview the question if the answer can't be
+		// decrypted, e.g. no private key configured.
+		return body, nil
+	}
+	answer := html.EscapeString(md.Frontmatter.Answer)
+	return body + "\n<hr>\n<pre class=\"learnevy-answer\">" + answer + "</pre>\n", nil
+}
+
+func renderMarkdown(content string) string {
+	parser := markdown.Parser{AutoLinkText: true, TaskListItems: true}
+	return markdown.ToHTML(parser.Parse(content))
+}
+
+func (s *previewServer) renderAndCache(path string) error {
+	body, err := s.renderFile(path)
+	if err != nil {
+		return fmt.Errorf("cannot render %s: %w", path, err)
+	}
+	rel, err := filepath.Rel(s.root, path)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.pages[filepath.ToSlash(rel)] = body
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *previewServer) handlePage(w http.ResponseWriter, r *http.Request) {
+	rel := strings.TrimPrefix(r.URL.Path, "/")
+	if rel == "" || strings.HasSuffix(rel, "/") {
+		rel += "README.md"
+	}
+	s.mu.RLock()
+	body, ok := s.pages[rel]
+	s.mu.RUnlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprintf(w, pageTemplate, html.EscapeString(rel), body)
+}
+
+const pageTemplate = `<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>%s</title></head>
+<body>
+%s
+<script>new EventSource("/events").onmessage = () => location.reload()</script>
+</body>
+</html>
+`
+
+func (s *previewServer) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	ch := make(chan struct{}, 1)
+	s.reloadMu.Lock()
+	s.reload[ch] = true
+	s.reloadMu.Unlock()
+	defer func() {
+		s.reloadMu.Lock()
+		delete(s.reload, ch)
+		s.reloadMu.Unlock()
+	}()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ch:
+			fmt.Fprint(w, "data: reload\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+func (s *previewServer) pushReload() {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+	for ch := range s.reload {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// addTreeToWatcher registers every directory under root with w, skipping
+// .git. fsnotify watches directories, not whole trees, so new files need
+// their parent directory watched, not the file itself.
+func addTreeToWatcher(w *fsnotify.Watcher, root string) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if info.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		return w.Add(path)
+	})
+}
+
+// watch consumes fsnotify events for markdown files, debouncing bursts of
+// writes into a single re-render and reload push per settled file.
+func (s *previewServer) watch(w *fsnotify.Watcher) {
+	var mu sync.Mutex
+	pending := map[string]bool{}
+	var timer *time.Timer
+
+	flush := func() {
+		mu.Lock()
+		paths := pending
+		pending = map[string]bool{}
+		mu.Unlock()
+		for path := range paths {
+			if err := s.renderAndCache(path); err != nil {
+				log.Printf("learnevy serve: %v", err)
+			}
+		}
+		s.pushReload()
+	}
+
+	for {
+		select {
+		case ev, ok := <-w.Events:
+			if !ok {
+				return
+			}
+			if !strings.HasSuffix(ev.Name, ".md") {
+				continue
+			}
+			if ev.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+			mu.Lock()
+			pending[ev.Name] = true
+			mu.Unlock()
+			if timer != nil {
+				timer.Stop()
+			}
+			timer = time.AfterFunc(debounceDelay, flush)
+		case err, ok := <-w.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("learnevy serve: watcher error: %v", err)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// matchesRun reports whether path (slash-separated, relative to a course
+// root, no extension) matches pattern, a slash-separated regexp in the
+// style of `go test -run`: each "/"-segment of pattern is anchored and
+// matched against the corresponding path component, so
+// "unit1/.*/question-link.*" selects every question in unit1 whose name
+// starts with "question-link". An empty pattern matches everything.
+func matchesRun(pattern, path string) (bool, error) {
+	if pattern == "" {
+		return true, nil
+	}
+	patternParts := strings.Split(pattern, "/")
+	pathParts := strings.Split(path, "/")
+	if len(patternParts) > len(pathParts) {
+		return false, nil
+	}
+	for i, p := range patternParts {
+		re, err := regexp.Compile("^(?:" + p + ")$")
+		if err != nil {
+			return false, fmt.Errorf("invalid -run pattern segment %q: %w", p, err)
+		}
+		if !re.MatchString(pathParts[i]) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// findQuestions walks root for *.md files, returning their paths as
+// filepath.Walk yields them (root joined with the file's relative path),
+// filtered by runPattern (see matchesRun) if non-empty. It skips
+// .git directories.
+func findQuestions(root, runPattern string) ([]string, error) {
+	var files []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".md") {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = strings.TrimSuffix(filepath.ToSlash(rel), ".md")
+		ok, err := matchesRun(runPattern, rel)
+		if err != nil {
+			return err
+		}
+		if ok {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files, err
+}
+
+// batchResult aggregates per-file outcomes across a directory batch run.
+type batchResult struct {
+	Passed []string
+	Failed map[string]error
+}
+
+func newBatchResult() *batchResult {
+	return &batchResult{Failed: map[string]error{}}
+}
+
+func (b *batchResult) record(path string, err error) {
+	if err != nil {
+		b.Failed[path] = err
+		return
+	}
+	b.Passed = append(b.Passed, path)
+}
+
+// report prints one failure line per failed file, in path order, plus a
+// final "N passed, M failed" summary.
+func (b *batchResult) report() {
+	paths := make([]string, 0, len(b.Failed))
+	for path := range b.Failed {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		fmt.Fprintf(os.Stderr, "FAIL %s: %v\n", path, b.Failed[path])
+	}
+	fmt.Printf("%d passed, %d failed\n", len(b.Passed), len(b.Failed))
+}
+
+// verifyReportVersion is embedded in every record printVerifyResult and
+// printVerifySummary emit, so a downstream consumer (CI, jq pipeline)
+// can detect a breaking schema change.
+const verifyReportVersion = 1
+
+// verifyResultRecord is one line of `levy verify --format=json` output,
+// describing the outcome for a single question file.
+type verifyResultRecord struct {
+	Version int    `json:"version"`
+	Type    string `json:"type"` // "result"
+	File    string `json:"file"`
+	Status  string `json:"status"` // "pass" or "fail"
+	Message string `json:"message,omitempty"`
+}
+
+// verifySummaryRecord is the final line of `levy verify --format=json`
+// output.
+type verifySummaryRecord struct {
+	Version int    `json:"version"`
+	Type    string `json:"type"` // "summary"
+	Total   int    `json:"total"`
+	Passed  int    `json:"passed"`
+	Failed  int    `json:"failed"`
+}
+
+// printVerifyResult writes one verifyResultRecord for file to stdout, in
+// the style of `go test -json`.
+func printVerifyResult(file string, err error) {
+	rec := verifyResultRecord{Version: verifyReportVersion, Type: "result", File: file, Status: "pass"}
+	if err != nil {
+		rec.Status = "fail"
+		rec.Message = err.Error()
+	}
+	b, _ := json.Marshal(rec)
+	fmt.Println(string(b))
+}
+
+// printVerifySummary writes the final verifySummaryRecord to stdout.
+func printVerifySummary(total, failed int) {
+	rec := verifySummaryRecord{
+		Version: verifyReportVersion,
+		Type:    "summary",
+		Total:   total,
+		Passed:  total - failed,
+		Failed:  failed,
+	}
+	b, _ := json.Marshal(rec)
+	fmt.Println(string(b))
+}
@@ -1,6 +1,6 @@
 // Levy is a tool for creating Evy practice and learn materials.
 //
-// Levy has the following sub-commands: export, verify, seal, unseal.
+// Levy has the following sub-commands: export, verify, watch, serve, seal, unseal.
 //
 //	Usage: levy <command> [flags]
 //
@@ -17,17 +17,48 @@
 //	  verify <md-file> [<type>] [flags]
 //	    Verify answers in markdown file.
 //
+//	  watch <path> [flags]
+//	    Watch a question tree and re-verify/export on every edit.
+//
+//	  serve <dir> [flags]
+//	    Serve a question tree for local preview and answer submission.
+//
 //	  seal <md-file> [flags]
 //	    Move 'answer' to 'sealed-answer' in source markdown.
 //
 //	  unseal <md-file> [flags]
 //	    Move 'sealed-answer' to 'answer' in source markdown.
 //
+//	export, verify, seal and unseal also accept a directory as <md-file>,
+//	recursively processing every question underneath it; -r/--run narrows
+//	the selection and --fail-fast stops at the first failure.
+//
+//	verify also accepts --format=json, streaming one JSON result object
+//	per file plus a final summary object, for consumption by CI systems.
+//
+//	export answerkey also accepts --exporter=firestore|csv|ndjson|sqlite,
+//	selecting the encoding for the exported answer key (default:
+//	firestore, the nested JSON shape Evy's Firestore backend expects).
+//
+//	export and verify also accept --watch, which performs the initial run
+//	as usual and then keeps watching <md-file> (or its directory), printing
+//	a colorized pass/fail line for every changed question or embedded
+//	*.evy/*.svg file, until interrupted. export --watch also rewrites the
+//	HTML of each question that passes, next to its source file; verify
+//	--watch writes no HTML. levy watch is the standalone form of the
+//	export loop.
+//
+//	serve renders every question in <dir> at a route derived from its
+//	path (e.g. /course1/unit1/exercise1/question1), accepts a submitted
+//	answer via POST to the same route, and, with --expose-key, serves the
+//	decrypted answer key for the whole tree at /api/answerkey.
+//
 //	Run "levy <command> --help" for more information on a command.
 package main
 
 import (
 	"cmp"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -46,6 +77,8 @@ var version = "v0.0.0"
 type app struct {
 	Export exportCmd `cmd:"" help:"Export answer key and HTML Files."`
 	Verify verifyCmd `cmd:"" help:"Verify answers in markdown file."`
+	Watch  watchCmd  `cmd:"" help:"Watch a question tree and re-verify/export on every edit."`
+	Serve  serveCmd  `cmd:"" help:"Serve a question tree for local preview and answer submission."`
 
 	Seal   sealCmd   `cmd:"" help:"Move 'answer' to 'sealed-answer' in source markdown."`
 	Unseal unsealCmd `cmd:"" help:"Move 'sealed-answer' to 'answer' in source markdown."`
@@ -64,37 +97,92 @@ func main() {
 	kctx.FatalIfErrorf(kctx.Run())
 }
 
+// batchFlags are embedded in every command whose MDFILE argument may
+// name a directory: MDFILE is then walked recursively for every *.md
+// question, RunPattern optionally narrows the selection, and FailFast
+// controls whether the batch stops at the first failure or continues
+// and reports a summary.
+type batchFlags struct {
+	RunPattern string `name:"run" short:"r" help:"When MDFILE is a directory, select a subset of questions: a slash-separated regexp where each /-segment matches one path component, e.g. \"unit1/.*/question-link.*\"."`
+	FailFast   bool   `help:"Stop at the first failure in batch mode, instead of continuing and reporting a summary."`
+}
+
 type exportCmd struct {
 	ExportType   string `arg:"" enum:"html,answerkey,all" help:"Export target: one of html, answerkey, all."`
-	MDFile       string `arg:"" help:"Question markdown file." placeholder:"MDFILE"`
+	MDFile       string `arg:"" help:"Question markdown file, or a directory to export every question under it." placeholder:"MDFILE"`
 	Target       string `arg:"" default:"-" help:"Output directory or JSON/HTML output file (default: . | stdout)." placeholder:"TARGET"`
 	UnsealedOnly bool   `short:"u" help:"Only export files with unsealed answers. Suitable if private key not available."`
 	PrivateKey   string `short:"k" help:"Secret private key to decrypt sealed answers." env:"EVY_LEARN_PRIVATE_KEY"`
+	Exporter     string `default:"firestore" enum:"firestore,csv,ndjson,sqlite" help:"Format for answer-key export: firestore (nested JSON), csv, ndjson or sqlite."`
+	Watch        bool   `help:"After the initial export, watch MDFILE for changes and re-export automatically."`
+	batchFlags
 
 	htmlPath      string
 	answerKeyPath string
 }
 
 type verifyCmd struct {
-	MDFile       string `arg:"" help:"Question markdown file." placeholder:"MDFILE"`
+	MDFile       string `arg:"" help:"Question markdown file, or a directory to verify every question under it." placeholder:"MDFILE"`
 	UnsealedOnly bool   `short:"u" help:"Only check result for files with unsealed answers. Suitable if private key not available."`
 	PrivateKey   string `short:"k" help:"Secret private key to decrypt sealed answers." env:"EVY_LEARN_PRIVATE_KEY"`
+	Format       string `default:"text" enum:"text,json" help:"Output format: text or one-JSON-object-per-line, suitable for CI."`
+	Watch        bool   `help:"After the initial verification, watch MDFILE for changes and re-verify automatically."`
+	batchFlags
 
 	// TODO
 	Type string `arg:"" default:"all" enum:"all,result,seal" help:"Type of verification to perform (currently unused)." hidden:""`
 }
 
 type sealCmd struct {
-	MDFile    string `arg:"" help:"Question markdown file." placeholder:"MDFILE"`
+	MDFile    string `arg:"" help:"Question markdown file, or a directory to seal every question under it." placeholder:"MDFILE"`
 	PublicKey string `short:"k" help:"Public key to seal answers, default: built-in key"`
+	batchFlags
 }
 
 type unsealCmd struct {
-	MDFile     string `arg:"" help:"Question markdown file." placeholder:"MDFILE"`
+	MDFile     string `arg:"" help:"Question markdown file, or a directory to unseal every question under it." placeholder:"MDFILE"`
 	PrivateKey string `short:"k" help:"Secret private key to decrypt sealed answers." env:"EVY_LEARN_PRIVATE_KEY"`
+	batchFlags
 }
 
 func (c *exportCmd) Run() error {
+	if err := c.runOnce(); err != nil {
+		return err
+	}
+	if !c.Watch {
+		return nil
+	}
+	return c.watch()
+}
+
+// watch re-exports HTML for every changed question under MDFile
+// (answer-key exports aren't watched, since the merged file is only
+// meaningful for a full batch run).
+func (c *exportCmd) watch() error {
+	dir := c.MDFile
+	if info, err := os.Stat(dir); err != nil {
+		return err
+	} else if !info.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+	cache := newModelCache(c.UnsealedOnly, c.PrivateKey)
+	w, err := newWatcher(dir, c.RunPattern, cache, cache.verifyAndExport)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("levy export --watch: watching %s\n", dir)
+	w.run()
+	return nil
+}
+
+func (c *exportCmd) runOnce() error {
+	info, err := os.Stat(c.MDFile)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return c.runBatch()
+	}
 	opts := getOptions(c.UnsealedOnly, c.PrivateKey)
 	model, err := question.NewModel(c.MDFile, opts...)
 	if err != nil {
@@ -104,11 +192,15 @@ func (c *exportCmd) Run() error {
 		return err
 	}
 	if c.ExportType == "answerkey" || c.ExportType == "all" {
-		answerKeyJSON, err := model.ExportAnswerKeyJSON()
+		answerKey, err := model.ExportAnswerKey()
+		if err != nil {
+			return err
+		}
+		exported, err := question.Exporters[c.Exporter].Export(answerKey)
 		if err != nil {
 			return err
 		}
-		if err := writeFileOrStdout(c.answerKeyPath, answerKeyJSON); err != nil {
+		if err := writeFileOrStdout(c.answerKeyPath, string(exported)); err != nil {
 			return err
 		}
 	}
@@ -120,6 +212,138 @@ func (c *exportCmd) Run() error {
 	return nil
 }
 
+// runBatch implements `levy export <type> <dir>`: walk dir for question
+// markdown files matching --run and export each, merging per-question
+// answer keys into one JSON tree keyed by question path, since an
+// answer key is useless scattered across hundreds of one-question files.
+func (c *exportCmd) runBatch() error {
+	files, err := findQuestions(c.MDFile, c.RunPattern)
+	if err != nil {
+		return err
+	}
+	wantAnswerKey := c.ExportType == "answerkey" || c.ExportType == "all"
+	wantHTML := c.ExportType == "html" || c.ExportType == "all"
+	htmlDir := c.Target
+	if htmlDir == "-" || htmlDir == "" {
+		htmlDir = "."
+	}
+
+	answerKeys := map[string]question.AnswerKey{}
+	result := newBatchResult()
+	for _, f := range files {
+		err := c.exportOne(f, wantAnswerKey, wantHTML, htmlDir, answerKeys)
+		result.record(f, err)
+		if err != nil && c.FailFast {
+			break
+		}
+	}
+
+	if wantAnswerKey && len(answerKeys) > 0 {
+		keys := make([]question.AnswerKey, 0, len(answerKeys))
+		for _, k := range answerKeys {
+			keys = append(keys, k)
+		}
+		merged, err := mergeAnswerKeys(keys)
+		if err != nil {
+			return err
+		}
+		exported, err := question.Exporters[c.Exporter].Export(merged)
+		if err != nil {
+			return err
+		}
+		if err := writeMergedAnswerKey(c.Target, question.Exporters[c.Exporter], exported); err != nil {
+			return err
+		}
+	}
+	result.report()
+	if len(result.Failed) > 0 {
+		return fmt.Errorf("%d of %d questions failed", len(result.Failed), len(files))
+	}
+	return nil
+}
+
+func (c *exportCmd) exportOne(f string, wantAnswerKey, wantHTML bool, htmlDir string, answerKeys map[string]question.AnswerKey) error {
+	opts := getOptions(c.UnsealedOnly, c.PrivateKey)
+	model, err := question.NewModel(f, opts...)
+	if err != nil {
+		return err
+	}
+	rel, err := filepath.Rel(c.MDFile, f)
+	if err != nil {
+		return err
+	}
+	id := strings.TrimSuffix(filepath.ToSlash(rel), ".md")
+	if wantAnswerKey {
+		answerKey, err := model.ExportAnswerKey()
+		if err != nil {
+			return err
+		}
+		answerKeys[id] = answerKey
+	}
+	if wantHTML {
+		htmlPath := filepath.Join(htmlDir, filepath.FromSlash(id)+".html")
+		if err := os.MkdirAll(filepath.Dir(htmlPath), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(htmlPath, []byte(model.ToHTML()), 0o666); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeMergedAnswerKey(target string, exporter question.AnswerKeyExporter, exported []byte) error {
+	defaultName := "answerkey." + exporter.Extension()
+	if target == "" || target == "-" {
+		target = defaultName
+	} else if info, err := os.Stat(target); err == nil && info.IsDir() {
+		target = filepath.Join(target, defaultName)
+	}
+	return os.WriteFile(target, exported, 0o666)
+}
+
+// mergeAnswerKeys combines several per-question AnswerKey values into
+// one, without depending on AnswerKey's concrete Go shape beyond it
+// being JSON-marshalable into nested string-keyed objects.
+func mergeAnswerKeys(keys []question.AnswerKey) (question.AnswerKey, error) {
+	combined := map[string]any{}
+	for _, k := range keys {
+		b, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		var tree map[string]any
+		if err := json.Unmarshal(b, &tree); err != nil {
+			return nil, err
+		}
+		mergeTree(combined, tree)
+	}
+	b, err := json.Marshal(combined)
+	if err != nil {
+		return nil, err
+	}
+	var merged question.AnswerKey
+	if err := json.Unmarshal(b, &merged); err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
+
+// mergeTree deep-merges src into dst at every level of nested
+// map[string]any, so combining two AnswerKeys that share a course/unit
+// path doesn't clobber either's exercises.
+func mergeTree(dst, src map[string]any) {
+	for k, v := range src {
+		if vm, ok := v.(map[string]any); ok {
+			if dm, ok := dst[k].(map[string]any); ok {
+				mergeTree(dm, vm)
+				continue
+			}
+		}
+		dst[k] = v
+	}
+}
+
 func writeFileOrStdout(filename, content string) error {
 	if filename == "-" {
 		fmt.Println(content)
@@ -147,16 +371,119 @@ func (c *exportCmd) setPaths() error {
 }
 
 func (c *verifyCmd) Run() error {
+	if err := c.runOnce(); err != nil {
+		return err
+	}
+	if !c.Watch {
+		return nil
+	}
+	return c.watch()
+}
+
+// watch re-verifies every changed question under MDFile, printing a
+// colorized pass/fail line per file instead of the --format text/json
+// report, which only makes sense for a single, completed run.
+func (c *verifyCmd) watch() error {
+	dir := c.MDFile
+	if info, err := os.Stat(dir); err != nil {
+		return err
+	} else if !info.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+	cache := newModelCache(c.UnsealedOnly, c.PrivateKey)
+	onChange := func(path string) error {
+		model, err := cache.get(path)
+		if err != nil {
+			printWatchResult(path, err)
+			return err
+		}
+		err = model.Verify()
+		printWatchResult(path, err)
+		return err
+	}
+	w, err := newWatcher(dir, c.RunPattern, cache, onChange)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("levy verify --watch: watching %s\n", dir)
+	w.run()
+	return nil
+}
+
+func (c *verifyCmd) runOnce() error {
+	info, err := os.Stat(c.MDFile)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return c.runBatch()
+	}
 	opts := getOptions(c.UnsealedOnly, c.PrivateKey)
 	model, err := question.NewModel(c.MDFile, opts...)
+	if err == nil {
+		err = model.Verify()
+	}
+	if c.Format == "json" {
+		printVerifyResult(c.MDFile, err)
+		failed := 0
+		if err != nil {
+			failed = 1
+		}
+		printVerifySummary(1, failed)
+		return err
+	}
+	return err
+}
+
+// runBatch implements `levy verify <dir>`: walk dir for question
+// markdown files matching --run, verifying each and continuing past
+// failures unless --fail-fast is set. With --format=json it streams one
+// JSON result object per file, followed by a final summary object,
+// instead of the default FAIL-lines-plus-count text report.
+func (c *verifyCmd) runBatch() error {
+	files, err := findQuestions(c.MDFile, c.RunPattern)
 	if err != nil {
 		return err
 	}
-	return model.Verify()
+	result := newBatchResult()
+	for _, f := range files {
+		opts := getOptions(c.UnsealedOnly, c.PrivateKey)
+		model, err := question.NewModel(f, opts...)
+		if err == nil {
+			err = model.Verify()
+		}
+		result.record(f, err)
+		if c.Format == "json" {
+			printVerifyResult(f, err)
+		}
+		if err != nil && c.FailFast {
+			break
+		}
+	}
+	if c.Format == "json" {
+		printVerifySummary(len(files), len(result.Failed))
+	} else {
+		result.report()
+	}
+	if len(result.Failed) > 0 {
+		return fmt.Errorf("%d of %d questions failed", len(result.Failed), len(files))
+	}
+	return nil
 }
 
 func (c *sealCmd) Run() error {
-	model, err := question.NewModel(c.MDFile)
+	info, err := os.Stat(c.MDFile)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return c.runBatch()
+	}
+	return c.sealOne(c.MDFile)
+}
+
+func (c *sealCmd) sealOne(path string) error {
+	model, err := question.NewModel(path)
 	if err != nil {
 		return err
 	}
@@ -167,14 +494,69 @@ func (c *sealCmd) Run() error {
 	return model.WriteFormatted()
 }
 
+// runBatch implements `levy seal <dir>`: walk dir for question markdown
+// files matching --run and seal each in place.
+func (c *sealCmd) runBatch() error {
+	files, err := findQuestions(c.MDFile, c.RunPattern)
+	if err != nil {
+		return err
+	}
+	result := newBatchResult()
+	for _, f := range files {
+		err := c.sealOne(f)
+		result.record(f, err)
+		if err != nil && c.FailFast {
+			break
+		}
+	}
+	result.report()
+	if len(result.Failed) > 0 {
+		return fmt.Errorf("%d of %d questions failed", len(result.Failed), len(files))
+	}
+	return nil
+}
+
 func (c *unsealCmd) Run() error {
-	model, err := question.NewModel(c.MDFile, question.WithPrivateKey(c.PrivateKey))
+	info, err := os.Stat(c.MDFile)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return c.runBatch()
+	}
+	return c.unsealOne(c.MDFile)
+}
+
+func (c *unsealCmd) unsealOne(path string) error {
+	model, err := question.NewModel(path, question.WithPrivateKey(c.PrivateKey))
 	if err != nil {
 		return err
 	}
 	return model.Unseal()
 }
 
+// runBatch implements `levy unseal <dir>`: walk dir for question
+// markdown files matching --run and unseal each in place.
+func (c *unsealCmd) runBatch() error {
+	files, err := findQuestions(c.MDFile, c.RunPattern)
+	if err != nil {
+		return err
+	}
+	result := newBatchResult()
+	for _, f := range files {
+		err := c.unsealOne(f)
+		result.record(f, err)
+		if err != nil && c.FailFast {
+			break
+		}
+	}
+	result.report()
+	if len(result.Failed) > 0 {
+		return fmt.Errorf("%d of %d questions failed", len(result.Failed), len(files))
+	}
+	return nil
+}
+
 type cryptoCmd struct {
 	Keygen keygenCryptoCmd `cmd:"" help:"Generate a new secret key."`
 	Seal   sealCryptoCmd   `cmd:"" help:"Encrypt a string given on command line"`
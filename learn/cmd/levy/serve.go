@@ -0,0 +1,200 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"evylang.dev/evy/learn/pkg/question"
+)
+
+// serveCmd runs a local preview HTTP server for a question tree: each
+// question is rendered at a route derived from its path, e.g.
+// /course1/unit1/exercise1/question1, and a submitted answer can be
+// checked against the frontmatter answer without exposing it.
+type serveCmd struct {
+	Dir        string `arg:"" type:"path" default:"." help:"Root of course/unit/exercise/question markdown tree to serve." placeholder:"DIR"`
+	Addr       string `default:"localhost:8082" help:"Address to listen on."`
+	PrivateKey string `short:"k" help:"Secret private key to decrypt sealed answers." env:"EVY_LEARN_PRIVATE_KEY"`
+	ExposeKey  bool   `help:"Expose the decrypted answer key for the whole tree at /api/answerkey."`
+}
+
+func (c *serveCmd) Run() error {
+	srv, err := newLevyServer(c.Dir, c.PrivateKey, c.ExposeKey)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("levy serve: listening on http://%s (dir %s)\n", c.Addr, c.Dir)
+	return http.ListenAndServe(c.Addr, srv.mux)
+}
+
+// levyServer holds every question Model under a course tree, loaded once
+// at startup, keyed by its route path.
+type levyServer struct {
+	questions map[string]*question.Model
+	exposeKey bool
+	mux       *http.ServeMux
+}
+
+func newLevyServer(dir, privateKey string, exposeKey bool) (*levyServer, error) {
+	s := &levyServer{questions: map[string]*question.Model{}, exposeKey: exposeKey}
+	opts := []question.Option{question.WithPrivateKey(privateKey)}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".md") {
+			return nil
+		}
+		model, err := question.NewModel(path, opts...)
+		if err != nil {
+			return nil // not every markdown file in the tree is a question
+		}
+		id := questionID(dir, path)
+		s.questions[id] = model
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.mux = http.NewServeMux()
+	s.mux.HandleFunc("GET /api/answerkey", s.handleAnswerKey)
+	s.mux.HandleFunc("GET /{path...}", s.handleQuestion)
+	s.mux.HandleFunc("POST /{path...}", s.handleSubmit)
+	return s, nil
+}
+
+func (s *levyServer) handleQuestion(w http.ResponseWriter, r *http.Request) {
+	id := strings.Trim(r.URL.Path, "/")
+	if id == "" {
+		s.writeIndex(w)
+		return
+	}
+	model, ok := s.questions[id]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprintf(w, questionPageTemplate, html.EscapeString(id), model.ToHTML())
+}
+
+func (s *levyServer) writeIndex(w http.ResponseWriter) {
+	ids := make([]string, 0, len(s.questions))
+	for id := range s.questions {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	sb := strings.Builder{}
+	sb.WriteString("<!DOCTYPE html>\n<html><head><meta charset=\"utf-8\"><title>levy serve</title></head><body>\n<ul>\n")
+	for _, id := range ids {
+		esc := html.EscapeString(id)
+		fmt.Fprintf(&sb, "<li><a href=\"/%s\">%s</a></li>\n", esc, esc)
+	}
+	sb.WriteString("</ul>\n</body></html>\n")
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, sb.String())
+}
+
+const questionPageTemplate = `<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>%s</title></head>
+<body>
+%s
+<form id="submit-form">
+  <input type="text" name="answer" placeholder="your answer">
+  <button type="submit">Check</button>
+</form>
+<p id="result"></p>
+<script>
+document.getElementById("submit-form").addEventListener("submit", async (e) => {
+  e.preventDefault()
+  const answer = e.target.answer.value
+  const res = await fetch(location.pathname, {method: "POST", body: JSON.stringify({answer})})
+  const body = await res.json()
+  document.getElementById("result").textContent = body.correct ? "Correct!" : "Incorrect."
+})
+</script>
+</body>
+</html>
+`
+
+type submitRequest struct {
+	Answer string `json:"answer"`
+}
+
+type submitResponse struct {
+	Correct bool `json:"correct"`
+}
+
+func (s *levyServer) handleSubmit(w http.ResponseWriter, r *http.Request) {
+	id := strings.Trim(r.URL.Path, "/")
+	model, ok := s.questions[id]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	var req submitRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	correct, err := model.CheckAnswer(req.Answer)
+	if err != nil {
+		http.Error(w, "internal error grading submission", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, submitResponse{Correct: correct})
+}
+
+// handleAnswerKey serves the decrypted answer key for the whole tree.
+// It responds 404 unless --expose-key is set, since the whole point of
+// sealing answers is to not serve them back out.
+func (s *levyServer) handleAnswerKey(w http.ResponseWriter, r *http.Request) {
+	if !s.exposeKey {
+		http.NotFound(w, r)
+		return
+	}
+	merged := map[string]json.RawMessage{}
+	for id, model := range s.questions {
+		answerKeyJSON, err := model.ExportAnswerKeyJSON()
+		if err != nil {
+			http.Error(w, "internal error exporting answer key", http.StatusInternalServerError)
+			return
+		}
+		merged[id] = json.RawMessage(answerKeyJSON)
+	}
+	writeJSON(w, merged)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// questionID derives a question's route path from its file path,
+// relative to root and without the .md extension.
+func questionID(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		rel = path
+	}
+	rel = strings.TrimSuffix(rel, filepath.Ext(rel))
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"evylang.dev/evy/learn/pkg/question"
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce coalesces bursts of filesystem events, e.g. an editor
+// that writes a file in several steps on every save, into a single
+// re-check.
+const watchDebounce = 100 * time.Millisecond
+
+type watchCmd struct {
+	Path         string `arg:"" type:"path" default:"." help:"Directory of question markdown files to watch." placeholder:"PATH"`
+	RunPattern   string `name:"run" short:"r" help:"Only watch questions matching this slash-separated regexp, as in batch mode."`
+	UnsealedOnly bool   `short:"u" help:"Only check result for files with unsealed answers. Suitable if private key not available."`
+	PrivateKey   string `short:"k" help:"Secret private key to decrypt sealed answers." env:"EVY_LEARN_PRIVATE_KEY"`
+}
+
+func (c *watchCmd) Run() error {
+	cache := newModelCache(c.UnsealedOnly, c.PrivateKey)
+	w, err := newWatcher(c.Path, c.RunPattern, cache, cache.verifyAndExport)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("levy watch: watching %s\n", c.Path)
+	w.run()
+	return nil
+}
+
+// modelCache holds parsed *question.Model values keyed by filename, so a
+// changed file's unaffected siblings aren't re-parsed on every event. A
+// cache entry is invalidated by mtime, and also tracks which embedded
+// *.evy/*.svg files a question references, so an edit to an embed can
+// invalidate every question that embeds it.
+type modelCache struct {
+	opts []question.Option
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	byEmbed map[string]map[string]bool // embed path -> set of question paths
+}
+
+type cacheEntry struct {
+	mtime  time.Time
+	model  *question.Model
+	embeds []string
+}
+
+func newModelCache(unsealedOnly bool, privateKey string) *modelCache {
+	return &modelCache{
+		opts:    getOptions(unsealedOnly, privateKey),
+		entries: map[string]cacheEntry{},
+		byEmbed: map[string]map[string]bool{},
+	}
+}
+
+// get returns the cached model for path, reparsing it if path's mtime
+// has changed (or it hasn't been parsed yet).
+func (c *modelCache) get(path string) (*question.Model, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	entry, ok := c.entries[path]
+	c.mu.Unlock()
+	if ok && entry.mtime.Equal(info.ModTime()) {
+		return entry.model, nil
+	}
+
+	model, err := question.NewModel(path, c.opts...)
+	if err != nil {
+		return nil, err
+	}
+	embeds := model.EmbeddedFiles()
+
+	c.mu.Lock()
+	for _, old := range entry.embeds {
+		delete(c.byEmbed[old], path)
+	}
+	for _, e := range embeds {
+		if c.byEmbed[e] == nil {
+			c.byEmbed[e] = map[string]bool{}
+		}
+		c.byEmbed[e][path] = true
+	}
+	c.entries[path] = cacheEntry{mtime: info.ModTime(), model: model, embeds: embeds}
+	c.mu.Unlock()
+	return model, nil
+}
+
+// affected returns every cached question path that embeds path, in
+// addition to path itself if it is a question markdown file. The cache
+// entries of the embedding questions are marked stale, so the next get
+// reparses them even though their own mtime is unchanged.
+func (c *modelCache) affected(path string) []string {
+	paths := map[string]bool{}
+	if strings.HasSuffix(path, ".md") {
+		paths[path] = true
+	}
+	c.mu.Lock()
+	for p := range c.byEmbed[path] {
+		paths[p] = true
+		if entry, ok := c.entries[p]; ok {
+			entry.mtime = time.Time{}
+			c.entries[p] = entry
+		}
+	}
+	c.mu.Unlock()
+	out := make([]string, 0, len(paths))
+	for p := range paths {
+		out = append(out, p)
+	}
+	return out
+}
+
+// verifyAndExport verifies path and writes its rendered HTML alongside
+// the source file, printing a colorized pass/fail line.
+func (c *modelCache) verifyAndExport(path string) error {
+	model, err := c.get(path)
+	if err != nil {
+		printWatchResult(path, err)
+		return err
+	}
+	err = model.Verify()
+	printWatchResult(path, err)
+	if err != nil {
+		return err
+	}
+	htmlPath := strings.TrimSuffix(path, filepath.Ext(path)) + ".html"
+	return os.WriteFile(htmlPath, []byte(model.ToHTML()), 0o666)
+}
+
+const (
+	ansiGreen = "\033[32m"
+	ansiRed   = "\033[31m"
+	ansiReset = "\033[0m"
+)
+
+func printWatchResult(path string, err error) {
+	if err != nil {
+		fmt.Printf("%sFAIL%s %s: %v\n", ansiRed, ansiReset, path, err)
+		return
+	}
+	fmt.Printf("%sPASS%s %s\n", ansiGreen, ansiReset, path)
+}
+
+// watcher drives an fsnotify.Watcher over a question tree, debouncing
+// bursts of events and invoking onChange once per settled, matching file.
+type watcher struct {
+	root       string
+	runPattern string
+	cache      *modelCache
+	onChange   func(path string) error
+	fsw        *fsnotify.Watcher
+}
+
+func newWatcher(root, runPattern string, cache *modelCache, onChange func(path string) error) (*watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("cannot start filesystem watcher: %w", err)
+	}
+	if err := addTreeToWatcher(fsw, root); err != nil {
+		fsw.Close()
+		return nil, err
+	}
+	return &watcher{root: root, runPattern: runPattern, cache: cache, onChange: onChange, fsw: fsw}, nil
+}
+
+// addTreeToWatcher registers every directory under root with w, skipping
+// .git. fsnotify watches directories, not whole trees, so new files need
+// their parent directory watched, not the file itself.
+func addTreeToWatcher(w *fsnotify.Watcher, root string) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if info.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		return w.Add(path)
+	})
+}
+
+// run consumes fsnotify events until the watcher is closed, debouncing
+// bursts of writes into a single onChange call per settled file.
+func (w *watcher) run() {
+	defer w.fsw.Close()
+
+	var mu sync.Mutex
+	pending := map[string]bool{}
+	var timer *time.Timer
+
+	flush := func() {
+		mu.Lock()
+		paths := pending
+		pending = map[string]bool{}
+		mu.Unlock()
+		for path := range paths {
+			for _, affected := range w.cache.affected(path) {
+				ok, err := matchesRun(w.runPattern, relSlash(w.root, affected))
+				if err != nil {
+					log.Printf("levy watch: %v", err)
+					continue
+				}
+				if !ok {
+					continue
+				}
+				if err := w.onChange(affected); err != nil {
+					log.Printf("levy watch: %v", err)
+				}
+			}
+		}
+	}
+
+	for {
+		select {
+		case ev, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			if !strings.HasSuffix(ev.Name, ".md") && !strings.HasSuffix(ev.Name, ".evy") && !strings.HasSuffix(ev.Name, ".svg") {
+				continue
+			}
+			if ev.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+			mu.Lock()
+			pending[ev.Name] = true
+			mu.Unlock()
+			if timer != nil {
+				timer.Stop()
+			}
+			timer = time.AfterFunc(watchDebounce, flush)
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("levy watch: watcher error: %v", err)
+		}
+	}
+}
+
+func relSlash(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		rel = path
+	}
+	return filepath.ToSlash(strings.TrimSuffix(rel, filepath.Ext(rel)))
+}
@@ -0,0 +1,226 @@
+package question
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	_ "modernc.org/sqlite"
+)
+
+// AnswerKeyExporter encodes an AnswerKey into a downstream-consumable
+// byte format, e.g. for ingestion into an analytics pipeline or an
+// alternative backend to Firestore.
+type AnswerKeyExporter interface {
+	// Export encodes key.
+	Export(key AnswerKey) ([]byte, error)
+	// ContentType is the MIME type of the bytes Export returns.
+	ContentType() string
+	// Extension is the file extension conventionally used for this
+	// format, without a leading dot.
+	Extension() string
+}
+
+// Exporters holds every AnswerKeyExporter known to this package, keyed by
+// the name used on the command line (e.g. `levy export answerkey
+// --exporter=csv`).
+var Exporters = map[string]AnswerKeyExporter{
+	"firestore": firestoreExporter{},
+	"csv":       csvExporter{},
+	"ndjson":    ndjsonExporter{},
+	"sqlite":    sqliteExporter{},
+}
+
+// answerKeyRow is one course/unit/exercise/question/answer tuple,
+// flattened out of the nested AnswerKey structure. AnswerKey's own Go
+// shape isn't depended on beyond being JSON-marshalable into nested
+// string-keyed maps, so exporters stay correct even if that shape grows
+// new fields.
+type answerKeyRow struct {
+	Course   string
+	Unit     string
+	Exercise string
+	Question string
+	Answer   string
+}
+
+// flattenAnswerKey round-trips key through JSON to walk it generically,
+// in course/unit/exercise/question order, without needing AnswerKey's
+// concrete Go type.
+func flattenAnswerKey(key AnswerKey) ([]answerKeyRow, error) {
+	b, err := json.Marshal(key)
+	if err != nil {
+		return nil, fmt.Errorf("cannot flatten answer key: %w", err)
+	}
+	var tree map[string]map[string]map[string]map[string]json.RawMessage
+	if err := json.Unmarshal(b, &tree); err != nil {
+		return nil, fmt.Errorf("cannot flatten answer key: %w", err)
+	}
+
+	var rows []answerKeyRow
+	for course, units := range tree {
+		for unit, exercises := range units {
+			for exercise, questions := range exercises {
+				for q, raw := range questions {
+					rows = append(rows, answerKeyRow{
+						Course:   course,
+						Unit:     unit,
+						Exercise: exercise,
+						Question: q,
+						Answer:   formatAnswer(raw),
+					})
+				}
+			}
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		switch {
+		case a.Course != b.Course:
+			return a.Course < b.Course
+		case a.Unit != b.Unit:
+			return a.Unit < b.Unit
+		case a.Exercise != b.Exercise:
+			return a.Exercise < b.Exercise
+		default:
+			return a.Question < b.Question
+		}
+	})
+	return rows, nil
+}
+
+// formatAnswer renders a single Answer's JSON representation as a flat
+// string, suitable for a CSV cell: "single" and "multi" style answers
+// (see Answer) are unwrapped, anything else falls back to its raw JSON.
+func formatAnswer(raw json.RawMessage) string {
+	var fields struct {
+		Single string   `json:"Single"`
+		Multi  []string `json:"Multi"`
+	}
+	if err := json.Unmarshal(raw, &fields); err == nil {
+		if fields.Single != "" {
+			return fields.Single
+		}
+		if len(fields.Multi) > 0 {
+			b, _ := json.Marshal(fields.Multi)
+			return string(b)
+		}
+	}
+	return string(raw)
+}
+
+// firestoreExporter encodes the AnswerKey as-is: the nested JSON object
+// Firestore expects at course/unit/exercise/question path.
+type firestoreExporter struct{}
+
+func (firestoreExporter) Export(key AnswerKey) ([]byte, error) {
+	return json.MarshalIndent(key, "", "  ")
+}
+
+func (firestoreExporter) ContentType() string { return "application/json" }
+func (firestoreExporter) Extension() string   { return "json" }
+
+// csvExporter flattens the AnswerKey into one row per question:
+// course,unit,exercise,question,answer.
+type csvExporter struct{}
+
+func (csvExporter) Export(key AnswerKey) ([]byte, error) {
+	rows, err := flattenAnswerKey(key)
+	if err != nil {
+		return nil, err
+	}
+	buf := &bytes.Buffer{}
+	w := csv.NewWriter(buf)
+	if err := w.Write([]string{"course", "unit", "exercise", "question", "answer"}); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		if err := w.Write([]string{row.Course, row.Unit, row.Exercise, row.Question, row.Answer}); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (csvExporter) ContentType() string { return "text/csv" }
+func (csvExporter) Extension() string   { return "csv" }
+
+// ndjsonExporter encodes the AnswerKey as one JSON object per line, one
+// per question, suitable for streaming into a data warehouse.
+type ndjsonExporter struct{}
+
+func (ndjsonExporter) Export(key AnswerKey) ([]byte, error) {
+	rows, err := flattenAnswerKey(key)
+	if err != nil {
+		return nil, err
+	}
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	for _, row := range rows {
+		if err := enc.Encode(row); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+func (ndjsonExporter) ContentType() string { return "application/x-ndjson" }
+func (ndjsonExporter) Extension() string   { return "ndjson" }
+
+// sqliteExporter writes the AnswerKey into a single-table SQLite file
+// with a normalized course/unit/exercise/question/answer schema. Export
+// returns the bytes of the resulting database file.
+type sqliteExporter struct{}
+
+func (sqliteExporter) Export(key AnswerKey) ([]byte, error) {
+	rows, err := flattenAnswerKey(key)
+	if err != nil {
+		return nil, err
+	}
+	tmp, err := os.CreateTemp("", "answerkey-*.sqlite")
+	if err != nil {
+		return nil, err
+	}
+	path := tmp.Name()
+	tmp.Close()
+	defer os.Remove(path)
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open sqlite export file: %w", err)
+	}
+	defer db.Close()
+
+	const schema = `CREATE TABLE answers (
+		course TEXT NOT NULL,
+		unit TEXT NOT NULL,
+		exercise TEXT NOT NULL,
+		question TEXT NOT NULL,
+		answer TEXT NOT NULL,
+		PRIMARY KEY (course, unit, exercise, question)
+	)`
+	if _, err := db.Exec(schema); err != nil {
+		return nil, fmt.Errorf("cannot create sqlite schema: %w", err)
+	}
+	for _, row := range rows {
+		_, err := db.Exec(
+			"INSERT INTO answers (course, unit, exercise, question, answer) VALUES (?, ?, ?, ?, ?)",
+			row.Course, row.Unit, row.Exercise, row.Question, row.Answer,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("cannot insert answer row: %w", err)
+		}
+	}
+	return os.ReadFile(path)
+}
+
+func (sqliteExporter) ContentType() string { return "application/vnd.sqlite3" }
+func (sqliteExporter) Extension() string   { return "db" }
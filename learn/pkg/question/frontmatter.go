@@ -2,6 +2,7 @@ package question
 
 import (
 	"fmt"
+	"reflect"
 	"slices"
 	"strings"
 
@@ -15,30 +16,30 @@ type frontmatter struct {
 	Substituions []map[string]string `yaml:"substitutions,omitempty"`
 	AnswerType   answerType          `yaml:"answer-type,omitempty"` // single-choice, multiple-choice, free-text, multiple-free-texts, program
 	Answer       string              `yaml:"answer,omitempty"`
-	SealedAnswer string              `yaml:"sealed-answer,omitempty"`
+	SealedAnswer sealedAnswer        `yaml:"sealed-answer,omitempty"`
 }
 
 func (f *frontmatter) validate() error {
 	if f.Type != "question" {
 		return fmt.Errorf("%w: want: %q, got: %q", ErrWrongFrontmatterType, "question", f.Type)
 	}
-	if f.Answer == "" && f.SealedAnswer == "" {
+	if f.Answer == "" && f.SealedAnswer.isZero() {
 		return fmt.Errorf("no answer found: %w", ErrNoFrontmatterAnswer)
 	}
-	if f.Answer != "" && f.SealedAnswer != "" {
+	if f.Answer != "" && !f.SealedAnswer.isZero() {
 		return fmt.Errorf("%w: sealed and unsealed answer found, only one allowed", ErrInvalidFrontmatter)
 	}
-	return nil
+	return f.SealedAnswer.validate()
 }
 
 func (f *frontmatter) getAnswer(privateKey string) (Answer, error) {
 	text := f.Answer
-	if f.SealedAnswer != "" && privateKey == "" {
+	if !f.SealedAnswer.isZero() && privateKey == "" {
 		return Answer{}, ErrSealedAnswerNoKey
 	}
-	if f.SealedAnswer != "" {
+	if !f.SealedAnswer.isZero() {
 		var err error
-		text, err = Decrypt(privateKey, f.SealedAnswer)
+		text, err = f.SealedAnswer.decrypt(privateKey)
 		if err != nil {
 			return Answer{}, err
 		}
@@ -49,8 +50,27 @@ func (f *frontmatter) getAnswer(privateKey string) (Answer, error) {
 	return NewAnswer(f.AnswerType, text)
 }
 
+// checkAnswer reports whether submission matches the question's answer,
+// decrypting a sealed answer with privateKey if necessary. It never
+// returns the decrypted answer to the caller, so a grading server can
+// use it without risking leaking the answer to a participant.
+func (f *frontmatter) checkAnswer(privateKey, submission string) (bool, error) {
+	want, err := f.getAnswer(privateKey)
+	if err != nil {
+		return false, err
+	}
+	got, err := NewAnswer(f.AnswerType, submission)
+	if err != nil {
+		return false, nil // malformed submission: wrong, not a grading error
+	}
+	return reflect.DeepEqual(want, got), nil
+}
+
+// Seal encrypts the unsealed answer for a single shared publicKey,
+// producing the legacy, back-compat plain-string sealed-answer form. Use
+// SealRecipients to seal for several independently revocable recipients.
 func (f *frontmatter) Seal(publicKey string) error {
-	if f.Answer == "" && f.SealedAnswer != "" {
+	if f.Answer == "" && !f.SealedAnswer.isZero() {
 		return nil // already sealed
 	}
 	if f.Answer == "" {
@@ -60,27 +80,164 @@ func (f *frontmatter) Seal(publicKey string) error {
 	if err != nil {
 		return err
 	}
-	f.SealedAnswer = sealed
+	f.SealedAnswer = sealedAnswer{single: sealed}
+	f.Answer = ""
+	return nil
+}
+
+// Recipient is one named recipient of a multi-recipient sealed answer: a
+// stable id (e.g. "teacher", "ci") paired with their public key.
+// Revoking a recipient is re-sealing the question without their entry.
+type Recipient struct {
+	ID        string
+	PublicKey string
+}
+
+// SealRecipients encrypts the unsealed answer separately for each
+// recipient, producing a recipient-id -> ciphertext sealed-answer
+// mapping so a course author can revoke one grader's access by
+// re-sealing without that recipient, and so each recipient id can act as
+// an audit trail of who is authorized on the question.
+func (f *frontmatter) SealRecipients(recipients []Recipient) error {
+	if f.Answer == "" && !f.SealedAnswer.isZero() {
+		return nil // already sealed
+	}
+	if f.Answer == "" {
+		return fmt.Errorf("cannot seal empty answer: %w", ErrNoFrontmatterAnswer)
+	}
+	seen := map[string]bool{}
+	ciphertexts := make(map[string]string, len(recipients))
+	for _, r := range recipients {
+		if r.ID == "" {
+			return fmt.Errorf("%w: recipient id must not be empty", ErrInvalidFrontmatter)
+		}
+		if seen[r.ID] {
+			return fmt.Errorf("%w: duplicate recipient id %q", ErrInvalidFrontmatter, r.ID)
+		}
+		seen[r.ID] = true
+		sealed, err := Encrypt(r.PublicKey, f.Answer)
+		if err != nil {
+			return fmt.Errorf("recipient %q: %w", r.ID, err)
+		}
+		ciphertexts[r.ID] = sealed
+	}
+	f.SealedAnswer = sealedAnswer{recipients: ciphertexts}
 	f.Answer = ""
 	return nil
 }
 
+// Unseal decrypts the sealed answer with privateKey, trying every
+// recipient's ciphertext in turn for a multi-recipient sealed answer and
+// succeeding on the first that decrypts.
 func (f *frontmatter) Unseal(privateKey string) error {
-	if f.Answer != "" && f.SealedAnswer == "" {
+	return f.UnsealAs("", privateKey)
+}
+
+// UnsealAs decrypts the sealed answer with privateKey. If recipientID is
+// set, only that recipient's ciphertext is tried; otherwise every
+// recipient is tried in turn (or, for a legacy single sealed-answer,
+// that lone ciphertext).
+func (f *frontmatter) UnsealAs(recipientID, privateKey string) error {
+	if f.Answer != "" && f.SealedAnswer.isZero() {
 		return nil // already unsealed
 	}
-	if f.SealedAnswer == "" {
+	if f.SealedAnswer.isZero() {
 		return fmt.Errorf("cannot unseal empty sealed-answer: %w", ErrNoFrontmatterAnswer)
 	}
-	unsealed, err := Decrypt(privateKey, f.SealedAnswer)
+	unsealed, err := f.SealedAnswer.decryptAs(recipientID, privateKey)
 	if err != nil {
 		return err
 	}
-	f.SealedAnswer = ""
+	f.SealedAnswer = sealedAnswer{}
 	f.Answer = unsealed
 	return nil
 }
 
+// sealedAnswer holds the encrypted form of a question's answer. It
+// marshals as a plain YAML string for the legacy, back-compat single-key
+// form, or as a recipient-id -> ciphertext mapping when the question is
+// sealed for multiple independently revocable recipients via
+// SealRecipients.
+type sealedAnswer struct {
+	single     string
+	recipients map[string]string
+}
+
+func (s sealedAnswer) isZero() bool {
+	return s.single == "" && len(s.recipients) == 0
+}
+
+// IsZero implements yaml.IsZeroer, so the `omitempty` tag on
+// frontmatter.SealedAnswer omits only an empty sealed answer. Without it
+// yaml.v3 treats a struct with only unexported fields as always zero.
+func (s sealedAnswer) IsZero() bool {
+	return s.isZero()
+}
+
+// validate checks that a recipient map, if present, has no empty
+// recipient ids. Duplicate ids cannot occur: yaml.v3 rejects duplicate
+// mapping keys when UnmarshalYAML decodes the recipient mapping.
+func (s sealedAnswer) validate() error {
+	for id := range s.recipients {
+		if id == "" {
+			return fmt.Errorf("%w: sealed-answer recipient id must not be empty", ErrInvalidFrontmatter)
+		}
+	}
+	return nil
+}
+
+func (s sealedAnswer) decrypt(privateKey string) (string, error) {
+	return s.decryptAs("", privateKey)
+}
+
+func (s sealedAnswer) decryptAs(recipientID, privateKey string) (string, error) {
+	if s.single != "" {
+		return Decrypt(privateKey, s.single)
+	}
+	if recipientID != "" {
+		ciphertext, ok := s.recipients[recipientID]
+		if !ok {
+			return "", fmt.Errorf("%w: no recipient %q on sealed-answer", ErrInvalidFrontmatter, recipientID)
+		}
+		return Decrypt(privateKey, ciphertext)
+	}
+	var lastErr error
+	for _, ciphertext := range s.recipients {
+		text, err := Decrypt(privateKey, ciphertext)
+		if err == nil {
+			return text, nil
+		}
+		lastErr = err
+	}
+	if lastErr == nil {
+		lastErr = ErrNoFrontmatterAnswer
+	}
+	return "", fmt.Errorf("private key did not decrypt any recipient's ciphertext: %w", lastErr)
+}
+
+func (s sealedAnswer) MarshalYAML() (any, error) {
+	if len(s.recipients) > 0 {
+		return s.recipients, nil
+	}
+	if s.single == "" {
+		return nil, nil
+	}
+	return s.single, nil
+}
+
+func (s *sealedAnswer) UnmarshalYAML(value *yaml.Node) error {
+	switch value.Kind {
+	case 0:
+		return nil
+	case yaml.ScalarNode:
+		return value.Decode(&s.single)
+	case yaml.MappingNode:
+		return value.Decode(&s.recipients)
+	default:
+		return fmt.Errorf("%w: sealed-answer must be a string or a recipient-id mapping", ErrInvalidFrontmatter)
+	}
+}
+
 type frontmatterType string
 
 var validFrontmatterTypes = []string{"course", "unit", "exercise", "question"}
@@ -117,6 +274,21 @@ func (s *difficulty) UnmarshalText(text []byte) error {
 	return unmarshalText("frontmatter 'difficulty'", validDifficultys, text, (*string)(s))
 }
 
+// points is the conventional MOTH-style score value for a difficulty:
+// easy and retriable questions are worth 1 point, medium 2, hard 4.
+// retriable questions allow unlimited grading attempts; the others
+// allow only one.
+func (s difficulty) points() int {
+	switch s {
+	case "medium":
+		return 2
+	case "hard":
+		return 4
+	default: // "easy", "retriable" or unset
+		return 1
+	}
+}
+
 func marshalText(fieldName, str string, validStrings []string) ([]byte, error) {
 	if !slices.Contains(validStrings, str) {
 		return nil, fmt.Errorf(`%w: marshal: invalid %s: %q, use one of %v`, ErrInvalidFrontmatter, fieldName, str, validStrings)
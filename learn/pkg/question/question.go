@@ -81,6 +81,65 @@ func (md *Markdown) Unseal(privateKey string) error {
 	return nil
 }
 
+// SealRecipients seals the unsealed answer in the Frontmatter separately
+// for each recipient, so each can later be independently revoked by
+// re-sealing without them.
+func (md *Markdown) SealRecipients(recipients []Recipient) error {
+	if err := md.Frontmatter.SealRecipients(recipients); err != nil {
+		return fmt.Errorf("%w (%s)", err, md.Filename)
+	}
+	return nil
+}
+
+// UnsealAs unseals the sealed answer in the Frontmatter using privateKey,
+// restricting the attempt to recipientID's ciphertext if it is set.
+func (md *Markdown) UnsealAs(recipientID, privateKey string) error {
+	if err := md.Frontmatter.UnsealAs(recipientID, privateKey); err != nil {
+		return fmt.Errorf("%w (%s)", err, md.Filename)
+	}
+	return nil
+}
+
+// CheckAnswer reports whether submission matches the (possibly sealed)
+// answer in the Frontmatter, without revealing the answer itself. key
+// decrypts a sealed answer if one is present.
+func (md *Markdown) CheckAnswer(key, submission string) (bool, error) {
+	ok, err := md.Frontmatter.checkAnswer(key, submission)
+	if err != nil {
+		return false, fmt.Errorf("%w (%s)", err, md.Filename)
+	}
+	return ok, nil
+}
+
+// Difficulty returns the question's frontmatter difficulty.
+func (md *Markdown) Difficulty() string {
+	return string(md.Frontmatter.Difficulty)
+}
+
+// Points returns the conventional MOTH-style score value for the
+// question's difficulty: easy/retriable=1, medium=2, hard=4.
+func (md *Markdown) Points() int {
+	return md.Frontmatter.Difficulty.points()
+}
+
+// Retriable reports whether the question allows unlimited grading
+// attempts, which is conventionally true only for difficulty
+// "retriable".
+func (md *Markdown) Retriable() bool {
+	return md.Frontmatter.Difficulty == "retriable"
+}
+
+// Stripped returns the question's markdown source with its answer and
+// sealed-answer fields removed from the frontmatter, safe to serve to a
+// participant who shouldn't see the answer.
+func (md *Markdown) Stripped() (string, error) {
+	fm := *md.Frontmatter
+	fm.Answer = ""
+	fm.SealedAnswer = sealedAnswer{}
+	stripped := &Markdown{Filename: md.Filename, Frontmatter: &fm, Doc: md.Doc}
+	return stripped.Format()
+}
+
 // Format formats YAML frontmatter, fenced by "---", followed by markdown
 // content.
 func (md *Markdown) Format() (string, error) {
@@ -107,7 +166,7 @@ func (md *Markdown) Verify(key string) error {
 // VerifyUnsealed checks unsealed answers only and ignores sealed ones.
 // For unsealed answers it performs a normal Verify.
 func (md *Markdown) VerifyUnsealed() error {
-	if md.Frontmatter.SealedAnswer != "" {
+	if !md.Frontmatter.SealedAnswer.isZero() {
 		return nil
 	}
 	_, err := md.getVerifiedAnswer("")
@@ -126,7 +185,7 @@ func (md *Markdown) ExportAnswerKey(key string) (AnswerKey, error) {
 // ExportAnswerKeyUnsealed returns the answerKey for the question Markdown
 // file if the answer is unsealed. It returns an empty AnswerKey otherwise.
 func (md *Markdown) ExportAnswerKeyUnsealed() (AnswerKey, error) {
-	if md.Frontmatter.SealedAnswer != "" {
+	if !md.Frontmatter.SealedAnswer.isZero() {
 		return AnswerKey{}, nil
 	}
 	answer, err := md.getVerifiedAnswer("")
@@ -136,6 +195,27 @@ func (md *Markdown) ExportAnswerKeyUnsealed() (AnswerKey, error) {
 	return NewAnswerKey(md.Filename, answer)
 }
 
+// ExportAnswerKeyAs returns the question's answer key encoded by
+// exporter, e.g. Exporters["csv"], instead of the Firestore-shaped JSON
+// ExportAnswerKey returns directly.
+func (md *Markdown) ExportAnswerKeyAs(key string, exporter AnswerKeyExporter) ([]byte, error) {
+	answerKey, err := md.ExportAnswerKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return exporter.Export(answerKey)
+}
+
+// ExportAnswerKeyUnsealedAs is ExportAnswerKeyUnsealed, encoded by
+// exporter.
+func (md *Markdown) ExportAnswerKeyUnsealedAs(exporter AnswerKeyExporter) ([]byte, error) {
+	answerKey, err := md.ExportAnswerKeyUnsealed()
+	if err != nil {
+		return nil, err
+	}
+	return exporter.Export(answerKey)
+}
+
 func (md *Markdown) getVerifiedAnswer(key string) (Answer, error) {
 	answer, err := md.Frontmatter.getAnswer(key)
 	if err != nil {
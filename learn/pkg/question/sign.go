@@ -0,0 +1,83 @@
+package question
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrBadSignature is returned when a detached signature does not verify
+// or a signature file is malformed.
+var ErrBadSignature = errors.New("signature verification failed")
+
+// sigCommentPrefix starts the human-readable comment line of a detached
+// signature file, signify-style: a comment naming the key to verify
+// with, followed by the base64 signature on its own line.
+const sigCommentPrefix = "untrusted comment: "
+
+// KeygenSign generates a new Ed25519 signing keypair, distinct from the
+// sealing keys Keygen and KeygenAge produce. Sealing keys encrypt
+// answers so they aren't committed in plaintext; signing keys
+// authenticate an already-exported answer key so it can travel through
+// an untrusted channel (CDN, email) and still be verified on arrival.
+// The signing public key is safe to publish alongside a course; the
+// signing private key, like any sealing private key, must stay secret.
+func KeygenSign() (Keys, error) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		return Keys{}, fmt.Errorf("cannot generate signing key: %w", err)
+	}
+	return Keys{
+		Private: base64.StdEncoding.EncodeToString(priv),
+		Public:  base64.StdEncoding.EncodeToString(pub),
+	}, nil
+}
+
+// Sign returns a base64-encoded Ed25519 signature of data using
+// privateKeyStr, a signing private key as produced by KeygenSign.
+func Sign(privateKeyStr string, data []byte) (string, error) {
+	priv, err := base64.StdEncoding.DecodeString(privateKeyStr)
+	if err != nil || len(priv) != ed25519.PrivateKeySize {
+		return "", fmt.Errorf("%w: invalid Ed25519 signing private key", ErrBadSignature)
+	}
+	sig := ed25519.Sign(ed25519.PrivateKey(priv), data)
+	return base64.StdEncoding.EncodeToString(sig), nil
+}
+
+// VerifySignature checks that sigB64 is a valid Ed25519 signature of
+// data under publicKeyStr, a signing public key as produced by
+// KeygenSign.
+func VerifySignature(publicKeyStr string, data []byte, sigB64 string) error {
+	pub, err := base64.StdEncoding.DecodeString(publicKeyStr)
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return fmt.Errorf("%w: invalid Ed25519 signing public key", ErrBadSignature)
+	}
+	sig, err := base64.StdEncoding.DecodeString(sigB64)
+	if err != nil {
+		return fmt.Errorf("%w: invalid signature encoding: %s", ErrBadSignature, err)
+	}
+	if !ed25519.Verify(ed25519.PublicKey(pub), data, sig) {
+		return fmt.Errorf("%w: signature does not match", ErrBadSignature)
+	}
+	return nil
+}
+
+// FormatSignatureFile renders a signify-style detached signature file: a
+// human-readable comment line naming the key to verify with, followed by
+// the base64 signature on its own line.
+func FormatSignatureFile(comment, sigB64 string) string {
+	return fmt.Sprintf("%s%s\n%s\n", sigCommentPrefix, comment, sigB64)
+}
+
+// ParseSignatureFile extracts the base64 signature from a detached
+// signature file produced by FormatSignatureFile, ignoring the comment
+// header line.
+func ParseSignatureFile(content string) (string, error) {
+	lines := strings.SplitN(strings.TrimRight(content, "\n"), "\n", 2)
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], sigCommentPrefix) {
+		return "", fmt.Errorf("%w: malformed signature file", ErrBadSignature)
+	}
+	return strings.TrimSpace(lines[1]), nil
+}
@@ -0,0 +1,10 @@
+package bytecode
+
+// WithOptimization toggles whether Compile runs the peephole Optimize
+// pass over the emitted Bytecode before returning it. Optimization is off
+// by default so callers that rely on exact, unoptimized instruction
+// offsets (e.g. source-mapped debuggers) keep working unchanged.
+func (c *Compiler) WithOptimization(enable bool) *Compiler {
+	c.optimize = enable
+	return c
+}
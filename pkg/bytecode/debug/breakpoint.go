@@ -0,0 +1,69 @@
+package debug
+
+import "evylang.dev/evy/pkg/bytecode"
+
+// Breakpoints pauses VM execution at configured source lines, or after a
+// single instruction when stepping, for a REPL/IDE debugger built on the
+// bytecode backend.
+type Breakpoints struct {
+	positions bytecode.Positions
+	lines     map[int]bool
+	stepping  bool
+	paused    chan struct{}
+	resume    chan struct{}
+}
+
+// NewBreakpoints returns a Breakpoints manager for a program compiled
+// with positions, ready to be attached via vm.SetOnExecHook.
+func NewBreakpoints(positions bytecode.Positions) *Breakpoints {
+	return &Breakpoints{
+		positions: positions,
+		lines:     map[int]bool{},
+		paused:    make(chan struct{}),
+		resume:    make(chan struct{}),
+	}
+}
+
+// SetBreakpoint arms a pause the next time line executes.
+func (b *Breakpoints) SetBreakpoint(line int) {
+	b.lines[line] = true
+}
+
+// ClearBreakpoint disarms a previously set line breakpoint.
+func (b *Breakpoints) ClearBreakpoint(line int) {
+	delete(b.lines, line)
+}
+
+// Hook is installed with vm.SetOnExecHook(b.Hook). It blocks the VM
+// goroutine whenever the current instruction's line has an armed
+// breakpoint, or when single-stepping, until Continue or Step is called.
+func (b *Breakpoints) Hook(_ *bytecode.VM, ip int, _ bytecode.Opcode) {
+	pos, ok := b.positions.PositionOf(ip)
+	if !ok {
+		return
+	}
+	if !b.stepping && !b.lines[pos.Line] {
+		return
+	}
+	b.paused <- struct{}{}
+	<-b.resume
+}
+
+// Continue resumes a paused VM until the next armed breakpoint.
+func (b *Breakpoints) Continue() {
+	b.stepping = false
+	b.resume <- struct{}{}
+}
+
+// Step resumes a paused VM for exactly one more instruction, then pauses
+// again regardless of breakpoints.
+func (b *Breakpoints) Step() {
+	b.stepping = true
+	b.resume <- struct{}{}
+}
+
+// WaitPaused blocks until the VM hits a breakpoint (or the next
+// instruction, while stepping), for a caller driving the debugger loop.
+func (b *Breakpoints) WaitPaused() {
+	<-b.paused
+}
@@ -0,0 +1,37 @@
+package debug
+
+import "evylang.dev/evy/pkg/bytecode"
+
+// Coverage collects which source lines were executed during a VM run, by
+// translating each executed instruction offset back to a source Position
+// via the Bytecode's Positions table.
+type Coverage struct {
+	positions bytecode.Positions
+	lines     map[int]int // source line -> hit count
+}
+
+// NewCoverage returns a Coverage collector for a program compiled with
+// positions, ready to be attached via vm.SetOnExecHook.
+func NewCoverage(positions bytecode.Positions) *Coverage {
+	return &Coverage{positions: positions, lines: map[int]int{}}
+}
+
+// Hook is installed with vm.SetOnExecHook(c.Hook).
+func (c *Coverage) Hook(_ *bytecode.VM, ip int, _ bytecode.Opcode) {
+	pos, ok := c.positions.PositionOf(ip)
+	if !ok {
+		return
+	}
+	c.lines[pos.Line]++
+}
+
+// Lines returns the hit count for every source line that executed at
+// least once.
+func (c *Coverage) Lines() map[int]int {
+	return c.lines
+}
+
+// Hit reports whether line was executed at least once.
+func (c *Coverage) Hit(line int) bool {
+	return c.lines[line] > 0
+}
@@ -0,0 +1,49 @@
+package debug
+
+import (
+	"fmt"
+	"io"
+
+	"evylang.dev/evy/pkg/bytecode"
+)
+
+// SourceMap maps instruction offsets back to source positions. It is an
+// alias for Positions, the type Coverage and Breakpoints already key off
+// of, so the CLI's `-trace` flag and an IDE debugger share one mechanism
+// instead of two competing ones.
+type SourceMap = bytecode.Positions
+
+// StreamTracer writes one line per executed instruction to an io.Writer
+// as it happens, for the `-trace` CLI flag. Unlike Tracer, it never
+// retains the trace in memory, so it is safe to attach to long-running
+// or unbounded programs.
+type StreamTracer struct {
+	w   io.Writer
+	err error
+}
+
+// NewStreamTracer returns a StreamTracer writing to w, ready to be
+// attached via vm.SetOnExecHook.
+func NewStreamTracer(w io.Writer) *StreamTracer {
+	return &StreamTracer{w: w}
+}
+
+// Hook is installed with vm.SetOnExecHook(st.Hook). It streams
+// "ip op stack-top" for each instruction; the first write error is
+// sticky and available via Err.
+func (st *StreamTracer) Hook(vm *bytecode.VM, ip int, op bytecode.Opcode) {
+	if st.err != nil {
+		return
+	}
+	top := "<empty>"
+	if stack := vm.StackSnapshot(); len(stack) > 0 {
+		top = fmt.Sprintf("%v", stack[len(stack)-1])
+	}
+	_, st.err = fmt.Fprintf(st.w, "%d %v %s\n", ip, op, top)
+}
+
+// Err returns the first error encountered writing to the underlying
+// io.Writer, if any.
+func (st *StreamTracer) Err() error {
+	return st.err
+}
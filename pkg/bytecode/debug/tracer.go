@@ -0,0 +1,35 @@
+// Package debug provides VM.OnExecHook consumers for Evy's bytecode
+// backend: an instruction tracer, a source-line coverage collector, and a
+// line-breakpoint stepping manager. None of these need to fork vm.go;
+// they only observe execution through the public hook.
+package debug
+
+import "evylang.dev/evy/pkg/bytecode"
+
+// ExecStep is one recorded instruction execution.
+type ExecStep struct {
+	IP int
+	Op bytecode.Opcode
+}
+
+// Tracer records the executed-instruction sequence of a VM run, e.g. for
+// golden-trace tests or a step-through debugger's instruction log.
+type Tracer struct {
+	Steps []ExecStep
+}
+
+// NewTracer returns a Tracer ready to be attached via vm.SetOnExecHook.
+func NewTracer() *Tracer {
+	return &Tracer{}
+}
+
+// Hook is installed with vm.SetOnExecHook(t.Hook).
+func (t *Tracer) Hook(_ *bytecode.VM, ip int, op bytecode.Opcode) {
+	t.Steps = append(t.Steps, ExecStep{IP: ip, Op: op})
+}
+
+// Reset clears the recorded trace, so the same Tracer can be reused
+// across multiple VM runs.
+func (t *Tracer) Reset() {
+	t.Steps = t.Steps[:0]
+}
@@ -0,0 +1,57 @@
+package bytecode
+
+// OnExecHook is invoked by Run just before dispatching each instruction,
+// so embedders can implement tracing, coverage collection, or
+// breakpoints on top of the VM without forking it. ip is the instruction
+// pointer of the opcode about to execute.
+type OnExecHook func(vm *VM, ip int, op Opcode)
+
+// SetOnExecHook installs hook, replacing any previously set hook. Passing
+// nil restores the default no-op behavior.
+func (vm *VM) SetOnExecHook(hook OnExecHook) {
+	vm.onExecHook = hook
+}
+
+// execHook calls vm.onExecHook if one is set, otherwise it is a no-op.
+func (vm *VM) execHook(ip int, op Opcode) {
+	if vm.onExecHook != nil {
+		vm.onExecHook(vm, ip, op)
+	}
+}
+
+// GlobalsLen reports how many global slots are currently populated, for
+// hooks that want to inspect VM state without reaching into unexported
+// fields.
+func (vm *VM) GlobalsLen() int {
+	return len(vm.globals)
+}
+
+// StackLen reports the current stack pointer, i.e. how many values are
+// live on the operand stack.
+func (vm *VM) StackLen() int {
+	return vm.sp
+}
+
+// InstructionPointer reports the offset, into Bytecode.Instructions, of
+// the instruction about to execute. It is only meaningful when called
+// from inside an OnExecHook.
+func (vm *VM) InstructionPointer() int {
+	return vm.ip
+}
+
+// StackSnapshot returns a copy of the live portion of the operand stack,
+// bottom first, safe for a hook to retain or inspect without racing the
+// VM's own mutation of its internal stack.
+func (vm *VM) StackSnapshot() []value {
+	snapshot := make([]value, vm.sp)
+	copy(snapshot, vm.stack[:vm.sp])
+	return snapshot
+}
+
+// Globals returns a copy of the currently populated global slots, safe
+// for a hook to retain or inspect.
+func (vm *VM) Globals() []value {
+	globals := make([]value, len(vm.globals))
+	copy(globals, vm.globals)
+	return globals
+}
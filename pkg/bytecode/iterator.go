@@ -0,0 +1,107 @@
+package bytecode
+
+import "fmt"
+
+// iterKind distinguishes what an iterFrame walks, since arrays, strings
+// and maps each advance and yield elements differently.
+type iterKind int
+
+const (
+	iterArray iterKind = iota
+	iterString
+	iterMap
+	iterRange
+)
+
+// iterFrame is one entry on vm.iterStack: the state of a single `for
+// range` loop. Strings iterate by rune, not byte, and maps capture their
+// key slice at push time so iteration order is stable even if the
+// underlying map is mutated mid-loop.
+type iterFrame struct {
+	kind     iterKind
+	elements []value // array elements, map values in key order, or range members
+	keys     []string
+	index    int
+}
+
+// ErrIterNotIterable is returned by OpIterPush when the popped value is
+// not an array, string, or map.
+var ErrIterNotIterable = fmt.Errorf("value is not iterable")
+
+// iterPush pushes a new iterFrame walking v onto vm.iterStack, per iterKind.
+func (vm *VM) iterPush(v value) error {
+	frame, err := newIterFrame(v)
+	if err != nil {
+		return err
+	}
+	vm.iterStack = append(vm.iterStack, frame)
+	return nil
+}
+
+func newIterFrame(v value) (iterFrame, error) {
+	switch v := v.(type) {
+	case arrayVal:
+		return iterFrame{kind: iterArray, elements: v.Elements}, nil
+	case stringVal:
+		runes := []rune(string(v))
+		elements := make([]value, len(runes))
+		for i, r := range runes {
+			elements[i] = stringVal(string(r))
+		}
+		return iterFrame{kind: iterString, elements: elements}, nil
+	case mapVal:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		elements := make([]value, len(keys))
+		for i, k := range keys {
+			elements[i] = v[k]
+		}
+		return iterFrame{kind: iterMap, elements: elements, keys: keys}, nil
+	case numVal:
+		// The `for range n` default-args form: the compiler constant-folds
+		// start=0/step=1 and pushes only the count. Multi-argument
+		// `for range start stop step` is not yet lowered through this
+		// path and still uses the OpStepRange sequence.
+		n := int(v)
+		elements := make([]value, n)
+		for i := 0; i < n; i++ {
+			elements[i] = numVal(i)
+		}
+		return iterFrame{kind: iterRange, elements: elements}, nil
+	default:
+		return iterFrame{}, fmt.Errorf("%w: %T", ErrIterNotIterable, v)
+	}
+}
+
+// iterNext advances the iterator on top of vm.iterStack. When exhausted
+// it reports done=true so the caller can jump to the loop's jump target;
+// otherwise it pushes the current element (and, for map iteration, the
+// key below it) and reports done=false.
+func (vm *VM) iterNext() (done bool, err error) {
+	if len(vm.iterStack) == 0 {
+		return false, fmt.Errorf("iterNext: no active iterator")
+	}
+	frame := &vm.iterStack[len(vm.iterStack)-1]
+	if frame.index >= len(frame.elements) {
+		return true, nil
+	}
+	i := frame.index
+	frame.index++
+	if frame.kind == iterMap {
+		if err := vm.push(stringVal(frame.keys[i])); err != nil {
+			return false, err
+		}
+	}
+	if err := vm.push(frame.elements[i]); err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
+// iterPop discards the iterator frame on top of vm.iterStack, called at
+// loop end and by `break`.
+func (vm *VM) iterPop() {
+	vm.iterStack = vm.iterStack[:len(vm.iterStack)-1]
+}
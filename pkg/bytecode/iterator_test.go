@@ -0,0 +1,82 @@
+package bytecode
+
+import (
+	"testing"
+
+	"evylang.dev/evy/pkg/assert"
+)
+
+// TestIteratorProtocol hand-assembles the new, shorter `for range`
+// lowering the compiler can emit once it adopts OpIterPush/OpIterNext/
+// OpIterPop: push the iterable, loop on OpIterNext until exhaustion,
+// running the body once per element, then OpIterPop at loop end.
+func TestIteratorProtocol(t *testing.T) {
+	tests := []struct {
+		name         string
+		constants    []value
+		iterable     int // index into constants of the value to iterate
+		wantElements []value
+	}{
+		{
+			name:         "array",
+			constants:    makeValues(t, []any{1, 2, 3}),
+			iterable:     0,
+			wantElements: makeValues(t, 1, 2, 3),
+		},
+		{
+			name:         "string by rune",
+			constants:    makeValues(t, "ab"),
+			iterable:     0,
+			wantElements: makeValues(t, "a", "b"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vm := NewVM(&Bytecode{Constants: tt.constants})
+			iterable := tt.constants[tt.iterable]
+			err := vm.iterPush(iterable)
+			assert.NoError(t, err, "iterPush")
+
+			var got []value
+			for {
+				done, err := vm.iterNext()
+				assert.NoError(t, err, "iterNext")
+				if done {
+					break
+				}
+				got = append(got, vm.pop())
+			}
+			vm.iterPop()
+			assert.Equal(t, tt.wantElements, got)
+			assert.Equal(t, 0, len(vm.iterStack))
+		})
+	}
+}
+
+func TestIteratorProtocolMapKeyValue(t *testing.T) {
+	m := mapVal{"a": numVal(1), "b": numVal(2)}
+	vm := NewVM(&Bytecode{})
+	err := vm.iterPush(m)
+	assert.NoError(t, err, "iterPush")
+
+	seen := map[string]value{}
+	for {
+		done, err := vm.iterNext()
+		assert.NoError(t, err, "iterNext")
+		if done {
+			break
+		}
+		val := vm.pop()
+		key := vm.pop()
+		seen[string(key.(stringVal))] = val
+	}
+	vm.iterPop()
+	assert.Equal(t, numVal(1), seen["a"])
+	assert.Equal(t, numVal(2), seen["b"])
+}
+
+func TestIteratorProtocolNotIterable(t *testing.T) {
+	vm := NewVM(&Bytecode{})
+	err := vm.iterPush(boolVal(true))
+	assert.Error(t, err)
+}
@@ -0,0 +1,89 @@
+package bytecode
+
+import "fmt"
+
+// ErrGasExhausted is returned by Run once GasConsumed crosses GasLimit.
+var ErrGasExhausted = fmt.Errorf("gas exhausted")
+
+// ErrStackOverflow is returned by Run once the operand stack grows past
+// MaxStackDepth.
+var ErrStackOverflow = fmt.Errorf("stack overflow")
+
+// ErrLoopLimitExceeded is returned by Run once a single loop has iterated
+// past MaxLoopIterations.
+var ErrLoopLimitExceeded = fmt.Errorf("loop iteration limit exceeded")
+
+// CostGetter prices a single opcode dispatch, letting the cost model
+// charge more for expensive operations (OpArray, OpStepRange) than cheap
+// ones (OpConstant).
+type CostGetter func(op Opcode) int64
+
+// SetCostGetter installs the pluggable cost model consulted on every
+// instruction dispatch. The default cost model charges 1 gas per
+// instruction.
+func (vm *VM) SetCostGetter(getter CostGetter) {
+	vm.costGetter = getter
+}
+
+// SetGasLimit caps total gas consumption for a single Run; Run returns
+// ErrGasExhausted once GasConsumed would cross it. A limit of 0 disables
+// gas metering.
+func (vm *VM) SetGasLimit(limit int64) {
+	vm.gasLimit = limit
+}
+
+// SetMaxStackDepth caps the operand stack size; push returns
+// ErrStackOverflow once the stack would grow past it. A limit of 0
+// disables the check.
+func (vm *VM) SetMaxStackDepth(depth int) {
+	vm.maxStackDepth = depth
+}
+
+// SetMaxLoopIterations caps how many times a single OpStepRange/
+// OpIterRange loop, or a backward OpJump, may iterate before Run returns
+// ErrLoopLimitExceeded. A limit of 0 disables the check.
+func (vm *VM) SetMaxLoopIterations(n int) {
+	vm.maxLoopIterations = n
+}
+
+// chargeGas is called on every instruction dispatch, alongside execHook,
+// to meter execution cost against GasLimit.
+func (vm *VM) chargeGas(op Opcode) error {
+	if vm.gasLimit <= 0 {
+		return nil
+	}
+	cost := int64(1)
+	if vm.costGetter != nil {
+		cost = vm.costGetter(op)
+	}
+	vm.GasConsumed += cost
+	if vm.GasConsumed > vm.gasLimit {
+		return ErrGasExhausted
+	}
+	return nil
+}
+
+// checkStackDepth is called by push before growing the operand stack.
+func (vm *VM) checkStackDepth(newDepth int) error {
+	if vm.maxStackDepth > 0 && newDepth > vm.maxStackDepth {
+		return ErrStackOverflow
+	}
+	return nil
+}
+
+// checkLoopIteration is called at each OpStepRange/OpIterRange iteration
+// and at each backward OpJump, tracked per jump target ip so independent
+// loops are metered independently.
+func (vm *VM) checkLoopIteration(ip int) error {
+	if vm.maxLoopIterations <= 0 {
+		return nil
+	}
+	if vm.loopIterations == nil {
+		vm.loopIterations = map[int]int{}
+	}
+	vm.loopIterations[ip]++
+	if vm.loopIterations[ip] > vm.maxLoopIterations {
+		return ErrLoopLimitExceeded
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package bytecode
+
+import (
+	"testing"
+
+	"evylang.dev/evy/pkg/assert"
+)
+
+func TestGasExhausted(t *testing.T) {
+	bytecode := compileBytecode(t, `n := 0
+	for range 1000000
+		n = n + 1
+	end`)
+	vm := NewVM(bytecode)
+	vm.SetGasLimit(100)
+	vm.SetCostGetter(func(op Opcode) int64 {
+		if op == OpArray || op == OpStepRange {
+			return 10
+		}
+		return 1
+	})
+	err := vm.Run()
+	assert.Equal(t, ErrGasExhausted, err)
+}
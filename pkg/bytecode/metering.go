@@ -0,0 +1,68 @@
+package bytecode
+
+import (
+	"context"
+	"fmt"
+)
+
+// ErrInstructionLimitExceeded is returned by Run once
+// InstructionsExecuted would cross the limit set by SetInstructionLimit.
+var ErrInstructionLimitExceeded = fmt.Errorf("instruction limit exceeded")
+
+// ctxCheckInterval is how often, in executed instructions, Run checks a
+// set context for cancellation. Checking on every instruction would add
+// measurable overhead to tight loops; checking only at loop back-edges
+// (backward OpJump) keeps the check cheap while still making a canceled
+// context take effect within one loop iteration.
+const ctxCheckInterval = 256
+
+// SetInstructionLimit caps the number of instructions Run will execute;
+// it returns ErrInstructionLimitExceeded once InstructionsExecuted would
+// cross limit. A limit of 0 disables the check. This bounds
+// long-running or malicious programs independently of gas metering
+// (SetGasLimit), which prices instructions unevenly rather than counting
+// them.
+func (vm *VM) SetInstructionLimit(limit uint64) {
+	vm.instructionLimit = limit
+}
+
+// SetContext arms cooperative cancellation: Run checks ctx for
+// cancellation at each backward OpJump (a loop's back-edge) and returns a
+// wrapped ctx.Err() promptly once canceled.
+func (vm *VM) SetContext(ctx context.Context) {
+	vm.ctx = ctx
+}
+
+// InstructionsExecuted reports how many instructions this VM has
+// dispatched so far, for reporting or for a caller tuning
+// SetInstructionLimit.
+func (vm *VM) InstructionsExecuted() uint64 {
+	return vm.instructionsExecuted
+}
+
+// checkInstructionLimit is called by Run on every instruction dispatch,
+// alongside chargeGas and execHook.
+func (vm *VM) checkInstructionLimit() error {
+	vm.instructionsExecuted++
+	if vm.instructionLimit > 0 && vm.instructionsExecuted > vm.instructionLimit {
+		return ErrInstructionLimitExceeded
+	}
+	return nil
+}
+
+// checkCanceled is called by Run at backward OpJump targets, the loop
+// back-edges emitted by the for-range compiler, so a canceled context
+// interrupts a spinning loop within ctxCheckInterval instructions rather
+// than only between statements.
+func (vm *VM) checkCanceled() error {
+	if vm.ctx == nil {
+		return nil
+	}
+	if vm.instructionsExecuted%ctxCheckInterval != 0 {
+		return nil
+	}
+	if err := vm.ctx.Err(); err != nil {
+		return fmt.Errorf("evaluation canceled: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package bytecode
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"evylang.dev/evy/pkg/assert"
+)
+
+func TestInstructionLimitExceeded(t *testing.T) {
+	bytecode := compileBytecode(t, `n := 0
+	for range 1000000
+		n = n + 1
+	end`)
+	vm := NewVM(bytecode)
+	vm.SetInstructionLimit(50)
+
+	err := vm.Run()
+	assert.Equal(t, ErrInstructionLimitExceeded, err)
+	assert.Equal(t, true, vm.InstructionsExecuted() >= 50)
+}
+
+func TestContextCancellation(t *testing.T) {
+	bytecode := compileBytecode(t, `n := 0
+	for range 1000000
+		n = n + 1
+	end`)
+	vm := NewVM(bytecode)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	vm.SetContext(ctx)
+
+	err := vm.Run()
+	assert.Error(t, err)
+	assert.Equal(t, true, errors.Is(err, context.Canceled))
+}
@@ -0,0 +1,7 @@
+package bytecode
+
+// OpIncGlobal increments the global at its operand index by 1 in place.
+// The optimizer emits it in place of the very common hidden loop-counter
+// bump `OpGetGlobal idx; OpConstant 1; OpAdd; OpSetGlobal idx`, saving
+// three instruction dispatches per loop iteration.
+const OpIncGlobal Opcode = 201
@@ -0,0 +1,18 @@
+package bytecode
+
+// OpIterPush pops an iterable (array, string, or map) off the value
+// stack and pushes a new iterator frame onto vm.iterStack, replacing the
+// hidden-index-global scheme the compiler previously used for `for
+// range`.
+const OpIterPush Opcode = 202
+
+// OpIterNext advances the iterator on top of vm.iterStack. On exhaustion
+// it jumps to its jump-target operand; otherwise it pushes the current
+// element (and, for a map, its key below it) onto the value stack and
+// falls through into the loop body. The index of an array, string or
+// range element is not pushed.
+const OpIterNext Opcode = 203
+
+// OpIterPop discards the iterator frame on top of vm.iterStack, executed
+// at loop end and by `break`.
+const OpIterPop Opcode = 204
@@ -0,0 +1,8 @@
+package bytecode
+
+// OpSyscall calls a host function registered in a HostFuncRegistry,
+// carrying the function's small integer ID as its operand. The VM's
+// SyscallHandler resolves the ID and performs the call; Opcode values
+// here start at a high offset to avoid colliding with the existing
+// opcode block.
+const OpSyscall Opcode = 200
@@ -0,0 +1,232 @@
+package bytecode
+
+import "encoding/binary"
+
+// instr is a decoded instruction used internally by Optimize. origOffset
+// is the byte offset of the first original instruction an instr stands
+// for, which lets later passes remap jump targets even after earlier
+// passes fuse or fold several instructions into one.
+type instr struct {
+	op         Opcode
+	operand    int
+	origOffset int
+}
+
+// Optimize runs a peephole pass over bc's instructions, returning a new
+// Bytecode with an equivalent but smaller and faster encoding. It never
+// mutates bc. Compiler.WithOptimization(true) runs this automatically
+// after every Compile; callers that build a Bytecode by other means
+// (e.g. LoadProgram) can call it directly.
+func Optimize(bc *Bytecode) *Bytecode {
+	instrs, ok := decodeInstrs(bc.Instructions)
+	if !ok {
+		// Unknown opcode: refuse to touch the program rather than risk
+		// silently mis-optimizing it.
+		return bc
+	}
+	targets := jumpTargets(instrs)
+
+	constants := append([]value(nil), bc.Constants...)
+	instrs = foldConstantArithmetic(instrs, &constants)
+	instrs = foldNegativeConstants(instrs, &constants)
+	instrs = fuseIncGlobal(instrs, constants)
+	instrs = eliminateDeadCode(instrs, targets)
+
+	newInstructions := encodeInstrs(instrs)
+	return &Bytecode{Instructions: newInstructions, Constants: constants}
+}
+
+// decodeInstrs decodes b into a flat instr slice, reporting false if b
+// contains an opcode Optimize does not know how to reason about.
+func decodeInstrs(b []byte) ([]instr, bool) {
+	var out []instr
+	ip := 0
+	for ip < len(b) {
+		op := Opcode(b[ip])
+		width, ok := operandWidth(op)
+		if !ok {
+			return nil, false
+		}
+		operand := 0
+		switch width {
+		case 2:
+			operand = int(binary.BigEndian.Uint16(b[ip+1 : ip+3]))
+		case 4:
+			operand = int(binary.BigEndian.Uint32(b[ip+1 : ip+5]))
+		}
+		out = append(out, instr{op: op, operand: operand, origOffset: ip})
+		ip += 1 + width
+	}
+	return out, true
+}
+
+// encodeInstrs re-encodes instrs, building a fresh byte stream and
+// remapping every OpJump/OpJumpOnFalse/OpIterNext operand from its original target
+// offset to the (possibly different) offset the target instruction now
+// lives at.
+func encodeInstrs(instrs []instr) []byte {
+	oldToNew := make(map[int]int, len(instrs))
+	offset := 0
+	for _, in := range instrs {
+		oldToNew[in.origOffset] = offset
+		width := operandWidths[in.op]
+		offset += 1 + width
+	}
+
+	buf := make([]byte, 0, offset)
+	for _, in := range instrs {
+		buf = append(buf, byte(in.op))
+		operand := in.operand
+		if in.op == OpJump || in.op == OpJumpOnFalse || in.op == OpIterNext {
+			operand = resolveTarget(oldToNew, instrs, in.operand)
+		}
+		width := operandWidths[in.op]
+		switch width {
+		case 2:
+			buf = append(buf, byte(operand>>8), byte(operand))
+		case 4:
+			var tmp [4]byte
+			binary.BigEndian.PutUint32(tmp[:], uint32(operand))
+			buf = append(buf, tmp[:]...)
+		}
+	}
+	return buf
+}
+
+// resolveTarget maps an original jump target offset to its new offset.
+// If the exact offset was eliminated (should not normally happen, since
+// eliminateDeadCode only removes code no jump targets), it falls back to
+// the nearest surviving instruction at or after the original target.
+func resolveTarget(oldToNew map[int]int, instrs []instr, target int) int {
+	if n, ok := oldToNew[target]; ok {
+		return n
+	}
+	best := -1
+	for _, in := range instrs {
+		if in.origOffset >= target && (best == -1 || in.origOffset < best) {
+			best = in.origOffset
+		}
+	}
+	if best == -1 {
+		// Target was past the end of the program, e.g. a jump to just
+		// past the last instruction; map to just past the new end.
+		if len(instrs) == 0 {
+			return 0
+		}
+		last := instrs[len(instrs)-1]
+		return oldToNew[last.origOffset] + 1 + operandWidths[last.op]
+	}
+	return oldToNew[best]
+}
+
+// jumpTargets collects every offset referenced by an OpJump/OpJumpOnFalse/
+// OpIterNext operand, so eliminateDeadCode never removes an instruction
+// something still jumps to.
+func jumpTargets(instrs []instr) map[int]bool {
+	targets := map[int]bool{}
+	for _, in := range instrs {
+		if in.op == OpJump || in.op == OpJumpOnFalse || in.op == OpIterNext {
+			targets[in.operand] = true
+		}
+	}
+	return targets
+}
+
+// fuseIncGlobal replaces the hidden loop-counter bump
+// `OpGetGlobal idx; OpConstant <const 1>; OpAdd; OpSetGlobal idx` with a
+// single OpIncGlobal idx.
+func fuseIncGlobal(instrs []instr, constants []value) []instr {
+	out := make([]instr, 0, len(instrs))
+	for i := 0; i < len(instrs); i++ {
+		if i+3 < len(instrs) &&
+			instrs[i].op == OpGetGlobal &&
+			instrs[i+1].op == OpConstant &&
+			constants[instrs[i+1].operand] == numVal(1) &&
+			instrs[i+2].op == OpAdd &&
+			instrs[i+3].op == OpSetGlobal &&
+			instrs[i].operand == instrs[i+3].operand {
+			out = append(out, instr{op: OpIncGlobal, operand: instrs[i].operand, origOffset: instrs[i].origOffset})
+			i += 3
+			continue
+		}
+		out = append(out, instrs[i])
+	}
+	return out
+}
+
+// foldNegativeConstants replaces `OpConstant idx; OpMinus`, where idx
+// names a numeric constant, with a single OpConstant referencing (or
+// appending) the negated value.
+func foldNegativeConstants(instrs []instr, constants *[]value) []instr {
+	out := make([]instr, 0, len(instrs))
+	for i := 0; i < len(instrs); i++ {
+		if i+1 < len(instrs) && instrs[i].op == OpConstant && instrs[i+1].op == OpMinus {
+			if n, ok := (*constants)[instrs[i].operand].(numVal); ok {
+				idx := internConstant(constants, numVal(-n))
+				out = append(out, instr{op: OpConstant, operand: idx, origOffset: instrs[i].origOffset})
+				i++
+				continue
+			}
+		}
+		out = append(out, instrs[i])
+	}
+	return out
+}
+
+// foldConstantArithmetic replaces `OpConstant a; OpConstant b; OpAdd`,
+// where both a and b name numeric constants, with a single OpConstant
+// referencing their precomputed sum.
+func foldConstantArithmetic(instrs []instr, constants *[]value) []instr {
+	out := make([]instr, 0, len(instrs))
+	for i := 0; i < len(instrs); i++ {
+		if i+2 < len(instrs) &&
+			instrs[i].op == OpConstant &&
+			instrs[i+1].op == OpConstant &&
+			instrs[i+2].op == OpAdd {
+			a, aok := (*constants)[instrs[i].operand].(numVal)
+			b, bok := (*constants)[instrs[i+1].operand].(numVal)
+			if aok && bok {
+				idx := internConstant(constants, a+b)
+				out = append(out, instr{op: OpConstant, operand: idx, origOffset: instrs[i].origOffset})
+				i += 2
+				continue
+			}
+		}
+		out = append(out, instrs[i])
+	}
+	return out
+}
+
+// internConstant returns the index of v in *constants, appending it if
+// not already present, so repeated folds don't bloat the pool.
+func internConstant(constants *[]value, v value) int {
+	for i, c := range *constants {
+		if c == v {
+			return i
+		}
+	}
+	*constants = append(*constants, v)
+	return len(*constants) - 1
+}
+
+// eliminateDeadCode drops instructions immediately following an
+// unconditional OpJump up to the next instruction any jump in the
+// program still targets, or the end of the stream.
+func eliminateDeadCode(instrs []instr, targets map[int]bool) []instr {
+	out := make([]instr, 0, len(instrs))
+	skipping := false
+	for _, in := range instrs {
+		if skipping {
+			if targets[in.origOffset] {
+				skipping = false
+			} else {
+				continue
+			}
+		}
+		out = append(out, in)
+		if in.op == OpJump {
+			skipping = true
+		}
+	}
+	return out
+}
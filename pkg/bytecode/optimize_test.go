@@ -0,0 +1,115 @@
+package bytecode
+
+import (
+	"testing"
+
+	"evylang.dev/evy/pkg/assert"
+	"evylang.dev/evy/pkg/parser"
+)
+
+func TestOptimizeIncGlobalFusion(t *testing.T) {
+	bc := compileBytecode(t, `x := 0
+	for range 10
+		x = x + 1
+	end
+	x = x`)
+
+	optimized := Optimize(bc)
+
+	assert.Equal(t, true, len(optimized.Instructions) < len(bc.Instructions))
+
+	gotGlobal := 0
+	for _, in := range decodeOrFail(t, optimized.Instructions) {
+		if in.op == OpIncGlobal {
+			gotGlobal++
+		}
+	}
+	assert.Equal(t, true, gotGlobal > 0)
+}
+
+func TestOptimizePreservesBehavior(t *testing.T) {
+	input := `x := 0
+	for range 10
+		x = x + 1
+	end
+	x = x`
+	bc := compileBytecode(t, input)
+	optimized := Optimize(bc)
+
+	vm := NewVM(optimized)
+	err := vm.Run()
+	assert.NoError(t, err, "optimized run")
+	assert.Equal(t, makeValue(t, 10), vm.lastPoppedStackElem())
+}
+
+func TestOptimizeConstantFolding(t *testing.T) {
+	bc := &Bytecode{
+		Constants: makeValues(t, 2, 3),
+		Instructions: makeInstructions(
+			mustMake(t, OpConstant, 0),
+			mustMake(t, OpConstant, 1),
+			mustMake(t, OpAdd),
+		),
+	}
+	optimized := Optimize(bc)
+	instrs := decodeOrFail(t, optimized.Instructions)
+	assert.Equal(t, 1, len(instrs))
+	assert.Equal(t, OpConstant, instrs[0].op)
+	assert.Equal(t, makeValue(t, 5), optimized.Constants[instrs[0].operand])
+}
+
+func TestOptimizeNegativeConstantFolding(t *testing.T) {
+	bc := &Bytecode{
+		Constants: makeValues(t, 7),
+		Instructions: makeInstructions(
+			mustMake(t, OpConstant, 0),
+			mustMake(t, OpMinus),
+		),
+	}
+	optimized := Optimize(bc)
+	instrs := decodeOrFail(t, optimized.Instructions)
+	assert.Equal(t, 1, len(instrs))
+	assert.Equal(t, OpConstant, instrs[0].op)
+	assert.Equal(t, makeValue(t, -7), optimized.Constants[instrs[0].operand])
+}
+
+func decodeOrFail(t *testing.T, b []byte) []instr {
+	t.Helper()
+	instrs, ok := decodeInstrs(b)
+	if !ok {
+		t.Fatalf("decodeInstrs: unknown opcode in %v", b)
+	}
+	return instrs
+}
+
+func benchmarkStepRange(b *testing.B, optimize bool) {
+	input := `n := 0
+	for range 1000000
+		n = n + 1
+	end
+	n = n`
+	program, err := parser.Parse(input, parser.Builtins{})
+	if err != nil {
+		b.Fatalf("parse: %v", err)
+	}
+	comp := NewCompiler()
+	if err := comp.Compile(program); err != nil {
+		b.Fatalf("compile: %v", err)
+	}
+	bc := comp.Bytecode()
+	if optimize {
+		bc = Optimize(bc)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		vm := NewVM(bc)
+		if err := vm.Run(); err != nil {
+			b.Fatalf("run: %v", err)
+		}
+	}
+}
+
+func BenchmarkStepRange(b *testing.B) {
+	b.Run("unoptimized", func(b *testing.B) { benchmarkStepRange(b, false) })
+	b.Run("optimized", func(b *testing.B) { benchmarkStepRange(b, true) })
+}
@@ -0,0 +1,28 @@
+package bytecode
+
+import "io"
+
+// Marshal writes bc's binary encoding to w. It is a thin wrapper around
+// WriteTo for callers that only care about the error, not the byte
+// count, e.g. `evy compile foo.evy > foo.evyc`.
+func (bc *Bytecode) Marshal(w io.Writer) error {
+	_, err := bc.WriteTo(w)
+	return err
+}
+
+// Unmarshal reads a Bytecode previously written by Marshal/WriteTo from
+// r, validating its header and version the same way LoadProgram does.
+func Unmarshal(r io.Reader) (*Bytecode, error) {
+	return LoadProgram(r)
+}
+
+// NewVMFromReader reads a serialized Bytecode from r and returns a VM
+// ready to Run it, so tools like the CLI and the web playground can ship
+// precompiled .evyc programs without re-running the parser/compiler.
+func NewVMFromReader(r io.Reader) (*VM, error) {
+	bc, err := Unmarshal(r)
+	if err != nil {
+		return nil, err
+	}
+	return NewVM(bc), nil
+}
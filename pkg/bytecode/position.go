@@ -0,0 +1,24 @@
+package bytecode
+
+import "evylang.dev/evy/pkg/parser"
+
+// Positions maps an instruction pointer (the offset of an opcode within
+// Instructions) to the source Position the Compiler was emitting code for
+// at the time, so tooling can translate VM-level execution back to Evy
+// source line/column without re-running the parser. Only instruction
+// offsets that start a new source statement or expression need an entry;
+// lookups should walk backward to the nearest populated offset.
+type Positions map[int]parser.Position
+
+// PositionOf returns the Position recorded at or before ip, and whether
+// any position information is available at all.
+func (p Positions) PositionOf(ip int) (parser.Position, bool) {
+	best, ok := parser.Position{}, false
+	bestIP := -1
+	for at, pos := range p {
+		if at <= ip && at > bestIP {
+			bestIP, best, ok = at, pos, true
+		}
+	}
+	return best, ok
+}
@@ -0,0 +1,328 @@
+package bytecode
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// formatVersion is bumped whenever the binary encoding below changes
+// incompatibly. LoadProgram rejects any other version.
+const formatVersion uint32 = 1
+
+// magic identifies a .evyc file before the version header, so loading an
+// unrelated file fails fast with a clear error instead of a confusing
+// decode panic.
+var magic = [4]byte{'e', 'v', 'y', 'c'}
+
+// ErrBadMagic is returned when r does not start with the .evyc magic
+// bytes.
+var ErrBadMagic = fmt.Errorf("not an evyc program")
+
+// ErrUnsupportedVersion is returned when r was encoded with a
+// formatVersion this build does not understand.
+var ErrUnsupportedVersion = fmt.Errorf("unsupported evyc format version")
+
+// ErrCorrupted is returned when the trailing CRC32 does not match the
+// decoded payload.
+var ErrCorrupted = fmt.Errorf("corrupted evyc program")
+
+// MarshalBinary encodes bc's instruction stream and constants pool into a
+// compact, portable binary blob: a magic/version header, length-prefixed
+// instructions, numeric constants as 8-byte big-endian values,
+// length-prefixed strings, recursively encoded arrays/maps, and a CRC32
+// trailer so corrupted files fail fast. No symbol metadata is encoded.
+func (bc *Bytecode) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := bc.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteTo writes bc's binary encoding to w, returning the number of bytes
+// written.
+func (bc *Bytecode) WriteTo(w io.Writer) (int64, error) {
+	var payload bytes.Buffer
+	payload.Write(magic[:])
+	writeUvarint(&payload, uint64(formatVersion))
+	writeBytes(&payload, bc.Instructions)
+	writeUvarint(&payload, uint64(len(bc.Constants)))
+	for _, c := range bc.Constants {
+		if err := writeValue(&payload, c); err != nil {
+			return 0, err
+		}
+	}
+	sum := crc32.ChecksumIEEE(payload.Bytes())
+	n, err := w.Write(payload.Bytes())
+	if err != nil {
+		return int64(n), err
+	}
+	if err := binary.Write(w, binary.BigEndian, sum); err != nil {
+		return int64(n), err
+	}
+	return int64(n) + 4, nil
+}
+
+// UnmarshalBinary decodes b into bc, the inverse of MarshalBinary.
+func (bc *Bytecode) UnmarshalBinary(b []byte) error {
+	decoded, err := ReadFrom(bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	*bc = *decoded
+	return nil
+}
+
+// ReadFrom decodes a Bytecode previously written by WriteTo/MarshalBinary
+// from r.
+func ReadFrom(r io.Reader) (*Bytecode, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 4 {
+		return nil, ErrCorrupted
+	}
+	payload, wantSum := data[:len(data)-4], data[len(data)-4:]
+	gotSum := crc32.ChecksumIEEE(payload)
+	if binary.BigEndian.Uint32(wantSum) != gotSum {
+		return nil, ErrCorrupted
+	}
+	br := bytes.NewReader(payload)
+	var gotMagic [4]byte
+	if _, err := io.ReadFull(br, gotMagic[:]); err != nil {
+		return nil, err
+	}
+	if gotMagic != magic {
+		return nil, ErrBadMagic
+	}
+	version, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, err
+	}
+	if uint32(version) != formatVersion {
+		return nil, ErrUnsupportedVersion
+	}
+	instructions, err := readBytes(br)
+	if err != nil {
+		return nil, err
+	}
+	numConstants, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, err
+	}
+	constants := make([]value, numConstants)
+	for i := range constants {
+		constants[i], err = readValue(br)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &Bytecode{Instructions: instructions, Constants: constants}, nil
+}
+
+// LoadProgram validates the header, rejects unknown opcodes and
+// out-of-range jump targets, and returns the decoded program ready for
+// NewVM. Tools that cache .evyc files or ship precompiled programs to
+// constrained runtimes should prefer this over ReadFrom directly.
+func LoadProgram(r io.Reader) (*Bytecode, error) {
+	bc, err := ReadFrom(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateProgram(bc); err != nil {
+		return nil, err
+	}
+	return bc, nil
+}
+
+// ErrInvalidOpcode is returned by LoadProgram when the instruction stream
+// contains a byte that is not a known Opcode.
+var ErrInvalidOpcode = fmt.Errorf("invalid opcode")
+
+// ErrInvalidJumpTarget is returned by LoadProgram when an OpJump/
+// OpJumpOnFalse operand points outside Instructions.
+var ErrInvalidJumpTarget = fmt.Errorf("invalid jump target")
+
+// operandWidths records the operand width, in bytes, of every opcode
+// this package knows how to emit. validateProgram uses it to walk the
+// instruction stream without a full decoder; an opcode missing here is
+// treated as invalid rather than silently mis-parsed.
+var operandWidths = map[Opcode]int{
+	OpConstant:    2,
+	OpGetGlobal:   2,
+	OpSetGlobal:   2,
+	OpArray:       2,
+	OpJump:        2,
+	OpJumpOnFalse: 2,
+	OpSyscall:     4,
+	OpAdd:         0,
+	OpMinus:       0,
+	OpEqual:       0,
+	OpIndex:       0,
+	OpIterRange:   0,
+	OpStepRange:   0,
+	OpIncGlobal:   2,
+	OpIterPush:    0,
+	OpIterNext:    2,
+	OpIterPop:     0,
+}
+
+// operandWidth reports the operand width of op and whether op is known.
+func operandWidth(op Opcode) (int, bool) {
+	width, ok := operandWidths[op]
+	return width, ok
+}
+
+func validateProgram(bc *Bytecode) error {
+	ip := 0
+	for ip < len(bc.Instructions) {
+		op := Opcode(bc.Instructions[ip])
+		width, ok := operandWidth(op)
+		if !ok {
+			return fmt.Errorf("%w: 0x%02x at offset %d", ErrInvalidOpcode, op, ip)
+		}
+		if op == OpJump || op == OpJumpOnFalse {
+			target := int(binary.BigEndian.Uint16(bc.Instructions[ip+1 : ip+3]))
+			if target < 0 || target > len(bc.Instructions) {
+				return fmt.Errorf("%w: %d at offset %d", ErrInvalidJumpTarget, target, ip)
+			}
+		}
+		ip += 1 + width
+	}
+	return nil
+}
+
+// value type tags used by writeValue/readValue.
+const (
+	tagNum byte = iota
+	tagString
+	tagBool
+	tagArray
+	tagMap
+)
+
+func writeValue(w *bytes.Buffer, v value) error {
+	switch v := v.(type) {
+	case numVal:
+		w.WriteByte(tagNum)
+		var bits [8]byte
+		binary.BigEndian.PutUint64(bits[:], uint64(v))
+		w.Write(bits[:])
+	case stringVal:
+		w.WriteByte(tagString)
+		writeBytes(w, []byte(v))
+	case boolVal:
+		w.WriteByte(tagBool)
+		if v {
+			w.WriteByte(1)
+		} else {
+			w.WriteByte(0)
+		}
+	case arrayVal:
+		w.WriteByte(tagArray)
+		writeUvarint(w, uint64(len(v.Elements)))
+		for _, e := range v.Elements {
+			if err := writeValue(w, e); err != nil {
+				return err
+			}
+		}
+	case mapVal:
+		w.WriteByte(tagMap)
+		writeUvarint(w, uint64(len(v)))
+		for k, e := range v {
+			writeBytes(w, []byte(k))
+			if err := writeValue(w, e); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("cannot serialize value of type %T", v)
+	}
+	return nil
+}
+
+func readValue(r *bytes.Reader) (value, error) {
+	tag, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	switch tag {
+	case tagNum:
+		var bits [8]byte
+		if _, err := io.ReadFull(r, bits[:]); err != nil {
+			return nil, err
+		}
+		return numVal(binary.BigEndian.Uint64(bits[:])), nil
+	case tagString:
+		b, err := readBytes(r)
+		if err != nil {
+			return nil, err
+		}
+		return stringVal(b), nil
+	case tagBool:
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		return boolVal(b != 0), nil
+	case tagArray:
+		n, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, err
+		}
+		elements := make([]value, n)
+		for i := range elements {
+			elements[i], err = readValue(r)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return arrayVal{Elements: elements}, nil
+	case tagMap:
+		n, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, err
+		}
+		m := mapVal{}
+		for i := uint64(0); i < n; i++ {
+			k, err := readBytes(r)
+			if err != nil {
+				return nil, err
+			}
+			v, err := readValue(r)
+			if err != nil {
+				return nil, err
+			}
+			m[string(k)] = v
+		}
+		return m, nil
+	}
+	return nil, fmt.Errorf("unknown value tag %d", tag)
+}
+
+func writeUvarint(w *bytes.Buffer, v uint64) {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	w.Write(buf[:n])
+}
+
+func writeBytes(w *bytes.Buffer, b []byte) {
+	writeUvarint(w, uint64(len(b)))
+	w.Write(b)
+}
+
+func readBytes(r *bytes.Reader) ([]byte, error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
@@ -0,0 +1,178 @@
+package bytecode
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"testing"
+
+	"evylang.dev/evy/pkg/assert"
+)
+
+func TestSerializeRoundTrip(t *testing.T) {
+	inputs := []string{
+		// TestVMGlobals
+		`x := 1
+		y := 2
+		x = x + y`,
+		// TestArrays
+		`x := [1, 2, 3]
+		y := x`,
+		// TestStepRange
+		`x := 0
+		for range 10
+			x = x + 1
+		end
+		x = x`,
+	}
+	for _, input := range inputs {
+		input := input
+		t.Run(input, func(t *testing.T) {
+			bc := compileBytecode(t, input)
+
+			var buf bytes.Buffer
+			n, err := bc.WriteTo(&buf)
+			assert.NoError(t, err, "WriteTo")
+			assert.Equal(t, int64(buf.Len()), n)
+
+			got, err := ReadFrom(&buf)
+			assert.NoError(t, err, "ReadFrom")
+			assert.Equal(t, bc.Instructions, got.Instructions)
+			assert.Equal(t, len(bc.Constants), len(got.Constants))
+
+			loaded, err := LoadProgram(bytes.NewReader(mustMarshal(t, bc)))
+			assert.NoError(t, err, "LoadProgram")
+			assert.Equal(t, bc.Instructions, loaded.Instructions)
+		})
+	}
+}
+
+func TestSerializeMarshalUnmarshalBinary(t *testing.T) {
+	bc := compileBytecode(t, `x := "hello"
+	y := {"a": 1, "b": 2}`)
+
+	b, err := bc.MarshalBinary()
+	assert.NoError(t, err, "MarshalBinary")
+
+	got := &Bytecode{}
+	err = got.UnmarshalBinary(b)
+	assert.NoError(t, err, "UnmarshalBinary")
+	assert.Equal(t, bc.Instructions, got.Instructions)
+}
+
+func TestSerializeCorruption(t *testing.T) {
+	bc := compileBytecode(t, `x := 1
+	x = x + 1`)
+	good := mustMarshal(t, bc)
+
+	tests := []struct {
+		name    string
+		corrupt func([]byte) []byte
+		wantErr error
+	}{
+		{
+			// Flip the magic bytes and recompute the CRC trailer so the
+			// corruption is only detected by the magic check, not by CRC.
+			name: "bad magic",
+			corrupt: func(b []byte) []byte {
+				b[0] ^= 0xff
+				return recrc(b)
+			},
+			wantErr: ErrBadMagic,
+		},
+		{
+			name: "flipped byte in payload, stale crc",
+			corrupt: func(b []byte) []byte {
+				b[len(b)/2] ^= 0xff
+				return b
+			},
+			wantErr: ErrCorrupted,
+		},
+		{
+			name: "truncated",
+			corrupt: func(b []byte) []byte {
+				return b[:len(b)/2]
+			},
+			wantErr: ErrCorrupted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			corrupted := tt.corrupt(append([]byte(nil), good...))
+			_, err := LoadProgram(bytes.NewReader(corrupted))
+			assert.Error(t, err)
+			assert.Equal(t, true, errors.Is(err, tt.wantErr))
+		})
+	}
+}
+
+// recrc recomputes and overwrites the trailing CRC32 of an encoded
+// program so that a deliberate payload mutation is judged solely by the
+// header/opcode validation, not rejected early by the checksum.
+func recrc(b []byte) []byte {
+	payload := b[:len(b)-4]
+	sum := crc32.ChecksumIEEE(payload)
+	binary.BigEndian.PutUint32(b[len(b)-4:], sum)
+	return b
+}
+
+func TestSerializeRandomCorruption(t *testing.T) {
+	bc := compileBytecode(t, `x := [1, 2, 3]
+	for i := range 3
+		x = x
+	end`)
+	good := mustMarshal(t, bc)
+
+	// Deterministic pseudo-corruption: flip one byte at a time across the
+	// whole encoding, CRC trailer included, and require LoadProgram to
+	// reject every mutation, never silently accepting a different, "valid"
+	// looking program.
+	for i := range good {
+		mutated := append([]byte(nil), good...)
+		mutated[i] ^= 0x55
+		_, err := LoadProgram(bytes.NewReader(mutated))
+		if err == nil {
+			t.Fatalf("byte %d: corruption went undetected", i)
+		}
+	}
+}
+
+func TestPersistRoundTripThroughVM(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  any
+	}{
+		{"globals", `x := 1
+		y := 2
+		x = x + y`, 3},
+		{"for range", `x := 0
+		for range 10
+			x = x + 1
+		end
+		x = x`, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bc := compileBytecode(t, tt.input)
+
+			var buf bytes.Buffer
+			err := bc.Marshal(&buf)
+			assert.NoError(t, err, "Marshal")
+
+			vm, err := NewVMFromReader(&buf)
+			assert.NoError(t, err, "NewVMFromReader")
+			err = vm.Run()
+			assert.NoError(t, err, "Run")
+			assert.Equal(t, makeValue(t, tt.want), vm.lastPoppedStackElem())
+		})
+	}
+}
+
+func mustMarshal(t *testing.T, bc *Bytecode) []byte {
+	t.Helper()
+	b, err := bc.MarshalBinary()
+	assert.NoError(t, err, "MarshalBinary")
+	return b
+}
@@ -0,0 +1,75 @@
+package bytecode
+
+import "fmt"
+
+// SyscallHandler resolves the small integer ID carried by OpSyscall to the
+// registered host function and invokes it against the running VM. It is
+// the extension point that lets embedders expose Go functionality
+// (filesystem, graphics primitives, network) to Evy bytecode programs
+// without forking the parser or compiler.
+type SyscallHandler func(vm *VM, id uint32) error
+
+// HostFunc is a single host function exposed to Evy programs: it receives
+// its arguments already popped off the VM stack, in call order, and
+// returns the value to push back, or an error that aborts the Run.
+type HostFunc func(args []value) (value, error)
+
+// HostFuncRegistry maps host function names to their implementation and
+// the sequential ID the Compiler embeds into OpSyscall operands.
+type HostFuncRegistry struct {
+	byName map[string]uint32
+	byID   []HostFunc
+}
+
+// NewHostFuncRegistry returns an empty registry, consulted by the
+// Compiler when it sees a call to an identifier it does not resolve as a
+// user or builtin function.
+func NewHostFuncRegistry() *HostFuncRegistry {
+	return &HostFuncRegistry{byName: map[string]uint32{}}
+}
+
+// RegisterHostFunc adds fn under name, returning the ID the Compiler
+// should embed into the OpSyscall it emits for calls to name.
+func (r *HostFuncRegistry) RegisterHostFunc(name string, fn HostFunc) uint32 {
+	id := uint32(len(r.byID))
+	r.byID = append(r.byID, fn)
+	r.byName[name] = id
+	return id
+}
+
+// Lookup returns the ID registered for name, if any.
+func (r *HostFuncRegistry) Lookup(name string) (uint32, bool) {
+	id, ok := r.byName[name]
+	return id, ok
+}
+
+// call invokes the host function registered under id against args,
+// returning ErrUnknownHostFunc if id is out of range.
+func (r *HostFuncRegistry) call(id uint32, args []value) (value, error) {
+	if int(id) >= len(r.byID) {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownHostFunc, id)
+	}
+	return r.byID[id](args)
+}
+
+// ErrUnknownHostFunc is returned when an OpSyscall carries an ID with no
+// registered host function, e.g. a stale .evyc compiled against a
+// different registry.
+var ErrUnknownHostFunc = fmt.Errorf("unknown host function")
+
+// syscallHandler adapts a HostFuncRegistry into the VM's SyscallHandler
+// hook: it pops the host function's argument count off the stack,
+// invokes the registered Go function, and pushes its result.
+func syscallHandler(registry *HostFuncRegistry, argc int) SyscallHandler {
+	return func(vm *VM, id uint32) error {
+		args := make([]value, argc)
+		for i := argc - 1; i >= 0; i-- {
+			args[i] = vm.pop()
+		}
+		result, err := registry.call(id, args)
+		if err != nil {
+			return err
+		}
+		return vm.push(result)
+	}
+}
@@ -0,0 +1,84 @@
+package bytecode
+
+import (
+	"fmt"
+	"io"
+)
+
+// Registry is the stable, serializable name for HostFuncRegistry: the
+// table of host functions an embedder exposes to compiled Evy bytecode
+// via OpSyscall.
+type Registry = HostFuncRegistry
+
+// NewRegistry is the Registry-named constructor alongside
+// NewHostFuncRegistry, kept for callers that think in terms of "the
+// syscall registry" rather than "host functions".
+func NewRegistry() *Registry {
+	return NewHostFuncRegistry()
+}
+
+// Names returns every registered host function name in id order, e.g.
+// ["print", "read", "sleep"] for DefaultRegistry. A caller persisting a
+// compiled program alongside a Registry should serialize this slice next
+// to the .evyc blob, since the compiler's OpSyscall operands are only
+// meaningful relative to the exact id assignment that produced them.
+func (r *Registry) Names() []string {
+	names := make([]string, len(r.byID))
+	for name, id := range r.byName {
+		names[id] = name
+	}
+	return names
+}
+
+// SetSyscallHandler installs the handler Run consults for OpSyscall
+// instructions, equivalent to assigning vm.SyscallHandler directly but
+// matching the setter style used by SetOnExecHook, SetGasLimit, etc.
+func (vm *VM) SetSyscallHandler(fn SyscallHandler) {
+	vm.SyscallHandler = fn
+}
+
+// IOStreams are the host streams the default builtins (print, read)
+// read from and write to.
+type IOStreams struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+}
+
+// DefaultRegistry returns a Registry with Evy's core host-dependent
+// builtins — print, read, and sleep — wired up over streams, the
+// minimum an embedder needs before it can run a program that does any
+// I/O at all. Graphics primitives are platform-specific and are left for
+// embedders (e.g. the web playground's canvas backend) to register
+// themselves.
+func DefaultRegistry(streams IOStreams, sleep func(seconds float64)) *Registry {
+	r := NewRegistry()
+	r.RegisterHostFunc("print", func(args []value) (value, error) {
+		for _, a := range args {
+			if _, err := fmt.Fprint(streams.Stdout, a); err != nil {
+				return nil, err
+			}
+		}
+		fmt.Fprintln(streams.Stdout)
+		return nil, nil
+	})
+	r.RegisterHostFunc("read", func(args []value) (value, error) {
+		var line string
+		_, err := fmt.Fscanln(streams.Stdin, &line)
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		return stringVal(line), nil
+	})
+	r.RegisterHostFunc("sleep", func(args []value) (value, error) {
+		if sleep == nil {
+			return nil, nil
+		}
+		n, ok := args[0].(numVal)
+		if !ok {
+			return nil, fmt.Errorf("sleep: expected num argument, got %T", args[0])
+		}
+		sleep(float64(n))
+		return nil, nil
+	})
+	return r
+}
@@ -0,0 +1,31 @@
+package bytecode
+
+import (
+	"testing"
+
+	"evylang.dev/evy/pkg/assert"
+)
+
+func TestRegistryFooSyscall(t *testing.T) {
+	registry := NewRegistry()
+	registry.RegisterHostFunc("foo", func(args []value) (value, error) {
+		return numVal(1), nil
+	})
+
+	bytecode := compileBytecode(t, "x := foo()")
+	vm := NewVM(bytecode)
+	vm.SetSyscallHandler(syscallHandler(registry, 0))
+	err := vm.Run()
+	assert.NoError(t, err, "runtime error")
+
+	got := vm.lastPoppedStackElem()
+	assert.Equal(t, makeValue(t, 1), got)
+}
+
+func TestRegistryNames(t *testing.T) {
+	registry := NewRegistry()
+	registry.RegisterHostFunc("print", func(args []value) (value, error) { return nil, nil })
+	registry.RegisterHostFunc("read", func(args []value) (value, error) { return nil, nil })
+
+	assert.Equal(t, []string{"print", "read"}, registry.Names())
+}
@@ -0,0 +1,25 @@
+package bytecode
+
+import (
+	"testing"
+
+	"evylang.dev/evy/pkg/assert"
+)
+
+func TestHostFunc(t *testing.T) {
+	registry := NewHostFuncRegistry()
+	registry.RegisterHostFunc("host_add", func(args []value) (value, error) {
+		a := args[0].(numVal)
+		b := args[1].(numVal)
+		return numVal(a + b), nil
+	})
+
+	bytecode := compileBytecode(t, "x := host_add(2, 3)")
+	vm := NewVM(bytecode)
+	vm.SyscallHandler = syscallHandler(registry, 2)
+	err := vm.Run()
+	assert.NoError(t, err, "runtime error")
+
+	got := vm.lastPoppedStackElem()
+	assert.Equal(t, makeValue(t, 5), got)
+}
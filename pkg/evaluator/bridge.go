@@ -0,0 +1,163 @@
+package evaluator
+
+import (
+	"fmt"
+	"reflect"
+
+	"evylang.dev/evy/pkg/parser"
+)
+
+// ValueFromNative converts a native Go value into the corresponding Evy
+// Value, so a host embedding Evy as a scripting layer can pass its own
+// data into a running program without hand-writing BuiltinFunc wrappers.
+// It supports map[string]any, []any, structs (field names become map
+// keys), numeric types, strings and bools.
+func ValueFromNative(v any) (Value, error) {
+	if v == nil {
+		return &Any{}, nil
+	}
+	rv := reflect.ValueOf(v)
+	return valueFromReflect(rv)
+}
+
+func valueFromReflect(rv reflect.Value) (Value, error) {
+	switch rv.Kind() {
+	case reflect.Bool:
+		return &Bool{Val: rv.Bool()}, nil
+	case reflect.String:
+		return &String{Val: rv.String()}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Num{Val: float64(rv.Int())}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Num{Val: float64(rv.Uint())}, nil
+	case reflect.Float32, reflect.Float64:
+		return &Num{Val: rv.Float()}, nil
+	case reflect.Interface:
+		return valueFromReflect(rv.Elem())
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return &Any{}, nil
+		}
+		return valueFromReflect(rv.Elem())
+	case reflect.Slice, reflect.Array:
+		elements := make([]Value, rv.Len())
+		for i := range elements {
+			elem, err := valueFromReflect(rv.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			elements[i] = &Any{Val: elem}
+		}
+		return &Array{Elements: &elements}, nil
+	case reflect.Map:
+		pairs := map[string]Value{}
+		order := make([]string, 0, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			key := fmt.Sprintf("%v", iter.Key().Interface())
+			val, err := valueFromReflect(iter.Value())
+			if err != nil {
+				return nil, err
+			}
+			pairs[key] = &Any{Val: val}
+			order = append(order, key)
+		}
+		return &Map{Pairs: pairs, Order: &order}, nil
+	case reflect.Struct:
+		return structToMap(rv)
+	}
+	return nil, fmt.Errorf("%w: cannot bridge native value of kind %s", ErrBadArguments, rv.Kind())
+}
+
+func structToMap(rv reflect.Value) (Value, error) {
+	t := rv.Type()
+	pairs := map[string]Value{}
+	order := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		val, err := valueFromReflect(rv.Field(i))
+		if err != nil {
+			return nil, err
+		}
+		pairs[field.Name] = &Any{Val: val}
+		order = append(order, field.Name)
+	}
+	return &Map{Pairs: pairs, Order: &order}, nil
+}
+
+// TypeFromNative returns the Evy *parser.Type that ValueFromNative
+// produces for a Go value of type t.
+func TypeFromNative(t reflect.Type) (*parser.Type, error) {
+	switch t.Kind() {
+	case reflect.Bool:
+		return parser.BOOL_TYPE, nil
+	case reflect.String:
+		return parser.STRING_TYPE, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return parser.NUM_TYPE, nil
+	case reflect.Interface:
+		return parser.ANY_TYPE, nil
+	case reflect.Ptr:
+		return TypeFromNative(t.Elem())
+	case reflect.Slice, reflect.Array:
+		sub, err := TypeFromNative(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &parser.Type{Name: parser.ARRAY, Sub: sub}, nil
+	case reflect.Map, reflect.Struct:
+		return &parser.Type{Name: parser.MAP, Sub: parser.ANY_TYPE}, nil
+	}
+	return nil, fmt.Errorf("%w: cannot derive an Evy type for %s", ErrBadArguments, t.Kind())
+}
+
+// RegisterNativeFunc synthesizes a BuiltinFunc and FuncDeclStmt from the
+// signature of the Go function fn using reflection, and registers them as
+// name, so hosts can expose arbitrary Go APIs to Evy programs without
+// writing a bespoke BuiltinFunc wrapper for each one (the way xyBuiltin,
+// numBuiltin, etc. are hand-written for the built-in graphics functions).
+func (b *Builtins) RegisterNativeFunc(name string, fn any) error {
+	fnVal := reflect.ValueOf(fn)
+	fnType := fnVal.Type()
+	if fnType.Kind() != reflect.Func {
+		return fmt.Errorf("%w: RegisterNativeFunc requires a function, got %s", ErrBadArguments, fnType.Kind())
+	}
+	params := make([]*parser.Var, fnType.NumIn())
+	for i := range params {
+		t, err := TypeFromNative(fnType.In(i))
+		if err != nil {
+			return err
+		}
+		params[i] = &parser.Var{Name: fmt.Sprintf("a%d", i), T: t}
+	}
+	returnType := parser.NONE_TYPE
+	if fnType.NumOut() > 0 {
+		var err error
+		returnType, err = TypeFromNative(fnType.Out(0))
+		if err != nil {
+			return err
+		}
+	}
+	decl := &parser.FuncDeclStmt{Name: name, Params: params, ReturnType: returnType}
+	b.RegisterBuiltin(name, decl, nativeBuiltinFunc(fnVal, fnType))
+	return nil
+}
+
+func nativeBuiltinFunc(fnVal reflect.Value, fnType reflect.Type) BuiltinFunc {
+	return func(_ *scope, args []Value) (Value, error) {
+		in := make([]reflect.Value, len(args))
+		for i, arg := range args {
+			in[i] = reflect.ValueOf(nativeFromValue(arg)).Convert(fnType.In(i))
+		}
+		out := fnVal.Call(in)
+		if len(out) == 0 {
+			return nil, nil
+		}
+		return ValueFromNative(out[0].Interface())
+	}
+}
@@ -8,7 +8,7 @@ import (
 	"strings"
 	"time"
 
-	"foxygo.at/evy/pkg/parser"
+	"evylang.dev/evy/pkg/parser"
 )
 
 type Builtin struct {
@@ -45,6 +45,10 @@ func (b BuiltinFunc) Type() ValueType { return BUILTIN }
 func (b BuiltinFunc) String() string  { return "builtin function" }
 
 func DefaultBuiltins(rt *Runtime) Builtins {
+	nowFn := rt.Time.Now
+	if nowFn == nil {
+		nowFn = time.Now
+	}
 	funcs := map[string]Builtin{
 		"read":   {Func: readFunc(rt.Read), Decl: readDecl},
 		"print":  {Func: printFunc(rt.Print), Decl: printDecl},
@@ -65,6 +69,24 @@ func DefaultBuiltins(rt *Runtime) Builtins {
 		"str2num":  {Func: BuiltinFunc(str2numFunc), Decl: str2numDecl},
 		"str2bool": {Func: BuiltinFunc(str2boolFunc), Decl: str2boolDecl},
 
+		"json":     {Func: BuiltinFunc(jsonFunc), Decl: jsonDecl},
+		"json2str": {Func: BuiltinFunc(json2strFunc), Decl: json2strDecl},
+
+		"fetch": {Func: fetchFunc(rt.HTTP.Get, rt.Print), Decl: fetchDecl},
+		"post":  {Func: postFunc(rt.HTTP.Post, rt.Print), Decl: postDecl},
+
+		"now":       {Func: nowFunc(nowFn), Decl: nowDecl},
+		"unix":      {Func: unixFunc(nowFn), Decl: unixDecl},
+		"monotonic": {Func: monotonicFunc(), Decl: monotonicDecl},
+		"timefmt":   {Func: BuiltinFunc(timefmtFunc), Decl: timefmtDecl},
+		"parsetime": {Func: BuiltinFunc(parsetimeFunc), Decl: parsetimeDecl},
+
+		"raise": {Func: BuiltinFunc(raiseFunc), Decl: raiseDecl},
+		// "recover" is special-cased in evalFunctionCall because it needs
+		// to call back into the Evaluator; recoverDecl is still registered
+		// here so the parser's type checking sees its signature.
+		"recover": {Decl: recoverDecl},
+
 		"len": {Func: BuiltinFunc(lenFunc), Decl: lenDecl},
 		"has": {Func: BuiltinFunc(hasFunc), Decl: hasDecl},
 		"del": {Func: BuiltinFunc(delFunc), Decl: delDecl},
@@ -113,8 +135,9 @@ func DefaultBuiltins(rt *Runtime) Builtins {
 		"animate": {Name: "animate", Params: numParam},
 	}
 	globals := map[string]*parser.Var{
-		"err":    {Name: "err", T: parser.BOOL_TYPE},
-		"errmsg": {Name: "errmsg", T: parser.STRING_TYPE},
+		"err":     {Name: "err", T: parser.BOOL_TYPE},
+		"errmsg":  {Name: "errmsg", T: parser.STRING_TYPE},
+		"lasterr": lasterrGlobal,
 	}
 	return Builtins{
 		EventHandlers: eventHandlers,
@@ -134,6 +157,8 @@ type Runtime struct {
 	Read     func() string
 	Sleep    func(dur time.Duration)
 	Graphics GraphicsRuntime
+	HTTP     HTTPRuntime
+	Time     TimeRuntime
 }
 
 type GraphicsRuntime struct {
@@ -0,0 +1,110 @@
+package evaluator
+
+import (
+	"fmt"
+
+	"evylang.dev/evy/pkg/parser"
+)
+
+// evyErrorType is the Evy-level shape of a structured error value: a map
+// with "kind" and "msg" string fields. Evy has no dedicated record type,
+// so errors are represented the same way decoded JSON objects are: a
+// *Map with Sub set to ANY_TYPE.
+var evyErrorType = &parser.Type{Name: parser.MAP, Sub: parser.ANY_TYPE}
+
+// newEvyError builds the Map representation of a structured error, used
+// for both the typed "lasterr" global and recover's return value.
+func newEvyError(kind, msg string) *Map {
+	pairs := map[string]Value{
+		"kind": &Any{Val: &String{Val: kind}},
+		"msg":  &Any{Val: &String{Val: msg}},
+	}
+	order := []string{"kind", "msg"}
+	return &Map{Pairs: pairs, Order: &order}
+}
+
+// emptyMap is recover's return value when f completes without error.
+func emptyMap() *Map {
+	order := []string{}
+	return &Map{Pairs: map[string]Value{}, Order: &order}
+}
+
+func setLastErr(scope *scope, kind, msg string) {
+	val, ok := scope.get("lasterr")
+	if !ok {
+		panic("cannot find global lasterr")
+	}
+	val.Set(newEvyError(kind, msg))
+}
+
+var lasterrGlobal = &parser.Var{Name: "lasterr", T: evyErrorType}
+
+var raiseDecl = &parser.FuncDeclStmt{
+	Name: "raise",
+	Params: []*parser.Var{
+		{Name: "kind", T: parser.STRING_TYPE},
+		{Name: "msg", T: parser.STRING_TYPE},
+	},
+	ReturnType: parser.NONE_TYPE,
+}
+
+// raiseFunc records a structured failure: it sets the legacy err/errmsg
+// globals for backwards compatibility, sets the typed lasterr global, and
+// returns a Go error so the call aborts the same way any other builtin
+// error does, letting an enclosing recover() catch it.
+func raiseFunc(scope *scope, args []Value) (Value, error) {
+	kind := args[0].(*String).Val
+	msg := args[1].(*String).Val
+	setGlobalErr(scope, kind+": "+msg)
+	setLastErr(scope, kind, msg)
+	return nil, fmt.Errorf("%s: %s", kind, msg)
+}
+
+var recoverDecl = &parser.FuncDeclStmt{
+	Name: "recover",
+	Params: []*parser.Var{
+		{Name: "f", T: parser.NewFuncType(&parser.FuncSig{ReturnType: parser.NONE_TYPE})},
+	},
+	ReturnType: evyErrorType,
+}
+
+// evalRecover runs f and reports whatever it failed with — a raise(),
+// a runtime error, or even a Go-level panic — as the typed Error map
+// instead of letting it propagate, returning an empty map when f
+// completes successfully.
+func (e *Evaluator) evalRecover(scope *scope, funcCall *parser.FunctionCall, args []Value) (result Value) {
+	defer func() {
+		if r := recover(); r != nil {
+			setLastErr(scope, "panic", fmt.Sprint(r))
+			setGlobalErr(scope, fmt.Sprint(r))
+			result = newEvyError("panic", fmt.Sprint(r))
+		}
+	}()
+	if len(args) == 1 && isError(args[0]) {
+		return args[0]
+	}
+	fn, ok := args[0].(*FuncValue)
+	if !ok {
+		return newRuntimeError(KindTypeMismatch, funcCall.Pos, "recover requires a function argument")
+	}
+	callScope := newInnerScope(fn.Scope)
+	val := e.Eval(callScope, fn.Decl.Body)
+	if isError(val) {
+		setLastErr(scope, "error", val.String())
+		return newEvyError("error", val.String())
+	}
+	return emptyMap()
+}
+
+// FUNC is the ValueType of a first-class function value.
+const FUNC ValueType = "func"
+
+// FuncValue is the runtime representation of a first-class function: the
+// declaration to call and the scope it closes over.
+type FuncValue struct {
+	Decl  *parser.FuncDeclStmt
+	Scope *scope
+}
+
+func (f *FuncValue) Type() ValueType { return FUNC }
+func (f *FuncValue) String() string  { return "func " + f.Decl.Name }
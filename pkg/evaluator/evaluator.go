@@ -4,7 +4,9 @@
 package evaluator
 
 import (
-	"foxygo.at/evy/pkg/parser"
+	"context"
+
+	"evylang.dev/evy/pkg/parser"
 )
 
 func Run(input string, printFn func(string)) {
@@ -12,13 +14,85 @@ func Run(input string, printFn func(string)) {
 }
 
 func RunWithBuiltins(input string, printFn func(string), builtins Builtins) {
+	RunWithOptions(input, printFn, builtins, DefaultRunOptions())
+}
+
+// RunWithContext behaves like Run but aborts evaluation early, returning a
+// "canceled"/"deadline exceeded" error value from Eval, once ctx is done.
+// This is the building block "evy serve" uses to enforce a per-request
+// execution timeout on user-submitted programs.
+func RunWithContext(ctx context.Context, input string, printFn func(string)) {
+	RunWithBuiltinsAndContext(ctx, input, printFn, DefaultBuiltins(printFn))
+}
+
+// Observer is notified before and after each node evaluation, letting
+// embedders implement step-through debugging, line breakpoints,
+// variable-watch panels and coverage highlighting on top of Evaluator
+// without forking it.
+type Observer interface {
+	// BeforeEval is called with the node about to be evaluated and the scope
+	// it will be evaluated in.
+	BeforeEval(node parser.Node, scope *scope)
+	// AfterEval is called with the node that was just evaluated and the
+	// resulting value.
+	AfterEval(node parser.Node, val Value)
+}
+
+// RunWithObserver behaves like RunWithBuiltins but reports every evaluation
+// step to observer, e.g. for a web front-end's step-through debugger.
+func RunWithObserver(input string, printFn func(string), builtins Builtins, observer Observer) {
+	opts := DefaultRunOptions()
+	opts.Observer = observer
+	RunWithOptions(input, printFn, builtins, opts)
+}
+
+// RunWithBuiltinsAndContext is RunWithBuiltins with cooperative cancellation
+// via ctx.
+func RunWithBuiltinsAndContext(ctx context.Context, input string, printFn func(string), builtins Builtins) {
+	opts := DefaultRunOptions()
+	opts.Context = ctx
+	RunWithOptions(input, printFn, builtins, opts)
+}
+
+// defaultMaxDepth bounds call/eval recursion so adversarial or accidental
+// unbounded recursion (e.g. "func f: f end") returns a runtime error
+// instead of exhausting the Go call stack, following the same defensive
+// pattern go/parser and encoding/gob use against hostile input.
+const defaultMaxDepth = 10_000
+
+// RunOptions configures a single Run/RunWithOptions invocation. Embedders
+// that run untrusted Evy programs (notably "evy serve") tune MaxDepth per
+// request.
+type RunOptions struct {
+	MaxDepth int
+	// Context, if non-nil, is checked at each statement and each loop
+	// iteration; evaluation stops early with a canceled/deadline-exceeded
+	// error value once it is done.
+	Context context.Context
+	// Observer, if non-nil, is notified before and after every node
+	// evaluation.
+	Observer Observer
+}
+
+// DefaultRunOptions returns the options used by Run/RunWithBuiltins.
+func DefaultRunOptions() RunOptions {
+	return RunOptions{MaxDepth: defaultMaxDepth}
+}
+
+// RunWithOptions behaves like RunWithBuiltins but allows the caller to tune
+// execution limits such as the recursion depth guard and cancellation.
+func RunWithOptions(input string, printFn func(string), builtins Builtins, opts RunOptions) {
 	p := parser.New(input, builtins.Decls())
 	prog := p.Parse()
 	if p.HasErrors() {
 		printFn(p.MaxErrorsString(8))
 		return
 	}
-	e := &Evaluator{print: printFn}
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	e := &Evaluator{print: printFn, maxDepth: opts.MaxDepth, ctx: ctx, observer: opts.Observer}
 	e.builtins = builtins
 	val := e.Eval(newScope(), prog)
 	if isError(val) {
@@ -29,9 +103,54 @@ func RunWithBuiltins(input string, printFn func(string), builtins Builtins) {
 type Evaluator struct {
 	print    func(string)
 	builtins map[string]Builtin
+
+	maxDepth int
+	depth    int
+	ctx      context.Context
+	observer Observer
+}
+
+// errDepthExceeded is returned once Eval recursion passes maxDepth, e.g. for
+// unbounded recursive functions.
+func (e *Evaluator) errDepthExceeded() Value {
+	return newError("evaluation depth exceeded")
+}
+
+// checkContext returns a non-nil error Value once e.ctx has been canceled or
+// its deadline has passed, nil otherwise. It is safe to call with a nil
+// e.ctx.
+func (e *Evaluator) checkContext() Value {
+	if e.ctx == nil {
+		return nil
+	}
+	if err := e.ctx.Err(); err != nil {
+		return newError(err.Error())
+	}
+	return nil
 }
 
 func (e *Evaluator) Eval(scope *scope, node parser.Node) Value {
+	if e.maxDepth > 0 {
+		e.depth++
+		defer func() { e.depth-- }()
+		if e.depth > e.maxDepth {
+			return e.errDepthExceeded()
+		}
+	}
+	if errVal := e.checkContext(); errVal != nil {
+		return errVal
+	}
+	if e.observer != nil {
+		e.observer.BeforeEval(node, scope)
+	}
+	val := e.evalNode(scope, node)
+	if e.observer != nil {
+		e.observer.AfterEval(node, val)
+	}
+	return val
+}
+
+func (e *Evaluator) evalNode(scope *scope, node parser.Node) Value {
 	switch node := node.(type) {
 	case *parser.Program:
 		return e.evalProgram(scope, node)
@@ -82,6 +201,9 @@ func (e *Evaluator) evalProgram(scope *scope, program *parser.Program) Value {
 func (e *Evaluator) evalStatments(scope *scope, statements []parser.Node) Value {
 	var result Value
 	for _, statement := range statements {
+		if errVal := e.checkContext(); errVal != nil {
+			return errVal
+		}
 		result = e.Eval(scope, statement)
 		if isError(result) || isReturn(result) || isBreak(result) {
 			return result
@@ -146,9 +268,16 @@ func (e *Evaluator) evalFunctionCall(scope *scope, funcCall *parser.FunctionCall
 	if len(args) == 1 && isError(args[0]) {
 		return args[0]
 	}
+	if funcCall.Name == "recover" {
+		return e.evalRecover(scope, funcCall, args)
+	}
 	builtin, ok := e.builtins[funcCall.Name]
 	if ok {
-		return builtin.Func(args)
+		val, err := builtin.Func(scope, args)
+		if err != nil {
+			return newError(err.Error())
+		}
+		return val
 	}
 	scope = innerScopeWithArgs(scope, funcCall.FuncDecl, args)
 	funcResult := e.Eval(scope, funcCall.FuncDecl.Body)
@@ -206,6 +335,9 @@ func (e *Evaluator) evalWhile(scope *scope, w *parser.While) Value {
 	whileBlock := &w.ConditionalBlock
 	val, ok := e.evalConditionalBlock(scope, whileBlock)
 	for ok && !isError(val) && !isReturn(val) && !isBreak(val) {
+		if errVal := e.checkContext(); errVal != nil {
+			return errVal
+		}
 		val, ok = e.evalConditionalBlock(scope, whileBlock)
 	}
 	return val
@@ -219,7 +351,7 @@ func (e *Evaluator) evalConditionalBlock(scope *scope, condBlock *parser.Conditi
 	}
 	boolCond, ok := cond.(*Bool)
 	if !ok {
-		return newError("conditional not a bool"), false
+		return newRuntimeError(KindConditionNotBool, condBlock.Pos, "conditional not a bool"), false
 	}
 	if boolCond.Val {
 		return e.Eval(scope, condBlock.Block), true
@@ -235,7 +367,7 @@ func (e *Evaluator) evalVar(scope *scope, v *parser.Var) Value {
 	if val, ok := scope.get(v.Name); ok {
 		return val
 	}
-	return newError("cannot find variable " + v.Name)
+	return newRuntimeError(KindUnknownVar, v.Pos, "cannot find variable "+v.Name)
 }
 
 func (e *Evaluator) evalExprList(scope *scope, terms []parser.Node) []Value {
@@ -263,12 +395,19 @@ func (e *Evaluator) evalUnaryExpr(scope *scope, expr *parser.UnaryExpression) Va
 		if op == parser.OP_MINUS {
 			return &Num{Val: -right.Val}
 		}
+		if op == parser.OP_TILDE {
+			i, ok := toInt(right.Val)
+			if !ok {
+				return newRuntimeError(KindTypeMismatch, expr.Pos, "bitwise not requires an integer operand, found "+right.String())
+			}
+			return &Num{Val: float64(^i)}
+		}
 	case *Bool:
 		if op == parser.OP_BANG {
 			return &Bool{Val: !right.Val}
 		}
 	}
-	return newError("unknown unary operation: " + expr.String())
+	return newRuntimeError(KindTypeMismatch, expr.Pos, "unknown unary operation: "+expr.String())
 }
 
 func (e *Evaluator) evalBinaryExpr(scope *scope, expr *parser.BinaryExpression) Value {
@@ -276,6 +415,9 @@ func (e *Evaluator) evalBinaryExpr(scope *scope, expr *parser.BinaryExpression)
 	if isError(left) {
 		return left
 	}
+	if val, ok := shortCircuit(expr.Op, left); ok {
+		return val
+	}
 	right := e.Eval(scope, expr.Right)
 	if isError(right) {
 		return right
@@ -289,16 +431,36 @@ func (e *Evaluator) evalBinaryExpr(scope *scope, expr *parser.BinaryExpression)
 	}
 	switch left := left.(type) {
 	case *Num:
-		return evalBinaryNumExpr(op, left, right.(*Num))
+		return evalBinaryNumExpr(op, left, right.(*Num), expr.Pos)
 	case *String:
-		return evalBinaryStringExpr(op, left, right.(*String))
+		return evalBinaryStringExpr(op, left, right.(*String), expr.Pos)
 	case *Bool:
-		return evalBinaryBoolExpr(op, left, right.(*Bool))
+		return evalBinaryBoolExpr(op, left, right.(*Bool), expr.Pos)
 	}
-	return newError("unknown binary operation: " + expr.String())
+	return newRuntimeError(KindTypeMismatch, expr.Pos, "unknown binary operation: "+expr.String())
 }
 
-func evalBinaryNumExpr(op parser.Operator, left, right *Num) Value {
+// shortCircuit evaluates the left-hand side of an "and"/"or" expression
+// only, returning the decided value and ok=true when the right-hand side
+// does not need to be (and must not be) evaluated: left is false for "and",
+// or left is true for "or". It returns ok=false for every other operator,
+// or when left doesn't already decide the result.
+func shortCircuit(op parser.Operator, left Value) (Value, bool) {
+	leftBool, ok := left.(*Bool)
+	if !ok {
+		return nil, false
+	}
+	switch {
+	case op == parser.OP_AND && !leftBool.Val:
+		return &Bool{Val: false}, true
+	case op == parser.OP_OR && leftBool.Val:
+		return &Bool{Val: true}, true
+	default:
+		return nil, false
+	}
+}
+
+func evalBinaryNumExpr(op parser.Operator, left, right *Num, pos parser.Position) Value {
 	switch op {
 	case parser.OP_PLUS:
 		return &Num{Val: left.Val + right.Val}
@@ -307,6 +469,9 @@ func evalBinaryNumExpr(op parser.Operator, left, right *Num) Value {
 	case parser.OP_ASTERISK:
 		return &Num{Val: left.Val * right.Val}
 	case parser.OP_SLASH:
+		if right.Val == 0 {
+			return newRuntimeError(KindDivideByZero, pos, "division by zero")
+		}
 		return &Num{Val: left.Val / right.Val}
 	case parser.OP_GT:
 		return &Bool{Val: left.Val > right.Val}
@@ -316,11 +481,60 @@ func evalBinaryNumExpr(op parser.Operator, left, right *Num) Value {
 		return &Bool{Val: left.Val >= right.Val}
 	case parser.OP_LTEQ:
 		return &Bool{Val: left.Val <= right.Val}
+	case parser.OP_PERCENT, parser.OP_SLASH_SLASH, parser.OP_AMP, parser.OP_PIPE, parser.OP_CARET, parser.OP_LSHIFT, parser.OP_RSHIFT:
+		return evalBinaryIntExpr(op, left, right, pos)
+	}
+	return newRuntimeError(KindTypeMismatch, pos, "unknown num operation: "+op.String())
+}
+
+// evalBinaryIntExpr handles the integer-only operators (modulo, floor
+// division, and the bitwise family), which operate on the integer portion
+// of left and right and reject non-integer operands.
+func evalBinaryIntExpr(op parser.Operator, left, right *Num, pos parser.Position) Value {
+	l, ok := toInt(left.Val)
+	if !ok {
+		return newRuntimeError(KindTypeMismatch, pos, "operator "+op.String()+" requires an integer left operand, found "+left.String())
+	}
+	r, ok := toInt(right.Val)
+	if !ok {
+		return newRuntimeError(KindTypeMismatch, pos, "operator "+op.String()+" requires an integer right operand, found "+right.String())
+	}
+	switch op {
+	case parser.OP_PERCENT:
+		if r == 0 {
+			return newRuntimeError(KindDivideByZero, pos, "division by zero")
+		}
+		return &Num{Val: float64(l % r)}
+	case parser.OP_SLASH_SLASH:
+		if r == 0 {
+			return newRuntimeError(KindDivideByZero, pos, "division by zero")
+		}
+		return &Num{Val: float64(l / r)}
+	case parser.OP_AMP:
+		return &Num{Val: float64(l & r)}
+	case parser.OP_PIPE:
+		return &Num{Val: float64(l | r)}
+	case parser.OP_CARET:
+		return &Num{Val: float64(l ^ r)}
+	case parser.OP_LSHIFT:
+		return &Num{Val: float64(l << r)}
+	case parser.OP_RSHIFT:
+		return &Num{Val: float64(l >> r)}
+	}
+	return newRuntimeError(KindTypeMismatch, pos, "unknown num operation: "+op.String())
+}
+
+// toInt reports whether v holds no fractional part and returns it as an
+// int64, for the integer-only operators (%, //, and the bitwise family).
+func toInt(v float64) (int64, bool) {
+	i := int64(v)
+	if float64(i) != v {
+		return 0, false
 	}
-	return newError("unknown num operation: " + op.String())
+	return i, true
 }
 
-func evalBinaryStringExpr(op parser.Operator, left, right *String) Value {
+func evalBinaryStringExpr(op parser.Operator, left, right *String, pos parser.Position) Value {
 	switch op {
 	case parser.OP_PLUS:
 		return &String{Val: left.Val + right.Val}
@@ -333,17 +547,17 @@ func evalBinaryStringExpr(op parser.Operator, left, right *String) Value {
 	case parser.OP_LTEQ:
 		return &Bool{left.Val <= right.Val}
 	}
-	return newError("unknown string operation: " + op.String())
+	return newRuntimeError(KindTypeMismatch, pos, "unknown string operation: "+op.String())
 }
 
-func evalBinaryBoolExpr(op parser.Operator, left, right *Bool) Value {
+func evalBinaryBoolExpr(op parser.Operator, left, right *Bool, pos parser.Position) Value {
 	switch op {
 	case parser.OP_AND:
 		return &Bool{Val: left.Val && right.Val}
 	case parser.OP_OR:
 		return &Bool{Val: left.Val || right.Val}
 	}
-	return newError("unknown bool operation: " + op.String())
+	return newRuntimeError(KindTypeMismatch, pos, "unknown bool operation: "+op.String())
 }
 
 func (e *Evaluator) evalIndexExpr(scope *scope, expr *parser.IndexExpression) Value {
@@ -364,7 +578,7 @@ func (e *Evaluator) evalIndexExpr(scope *scope, expr *parser.IndexExpression) Va
 	case *Map:
 		strIndex, ok := index.(*String)
 		if !ok {
-			return newError("expected string for map index, found " + index.String())
+			return newRuntimeError(KindTypeMismatch, expr.Pos, "expected string for map index, found "+index.String())
 		}
 		return l.Get(strIndex.Val)
 	}
@@ -378,7 +592,7 @@ func (e *Evaluator) evalDotExpr(scope *scope, expr *parser.DotExpression) Value
 	}
 	m, ok := left.(*Map)
 	if !ok {
-		return newError("expected map before '.', found " + left.String())
+		return newRuntimeError(KindTypeMismatch, expr.Pos, "expected map before '.', found "+left.String())
 	}
 	return m.Get(expr.Key)
 }
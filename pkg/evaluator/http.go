@@ -0,0 +1,67 @@
+package evaluator
+
+import "evylang.dev/evy/pkg/parser"
+
+// HTTPRuntime lets a host (browser, CLI, test harness) provide the actual
+// network transport behind the fetch/post builtins. In the browser this
+// cooperates with the existing Sleep-style yielding pattern: Get/Post
+// blocks the calling goroutine until the JS host resolves the underlying
+// fetch Promise and calls back, then returns.
+type HTTPRuntime struct {
+	Get  func(url string) (status int, body string, err error)
+	Post func(url, body string) (status int, respBody string, err error)
+}
+
+var fetchDecl = &parser.FuncDeclStmt{
+	Name:       "fetch",
+	Params:     []*parser.Var{{Name: "url", T: parser.STRING_TYPE}},
+	ReturnType: parser.STRING_TYPE,
+}
+
+func fetchFunc(httpFn func(string) (int, string, error), printFn func(string)) BuiltinFunc {
+	if httpFn == nil {
+		return notImplementedFunc("fetch", printFn)
+	}
+	return func(scope *scope, args []Value) (Value, error) {
+		resetGlobalErr(scope)
+		url := args[0].(*String).Val
+		status, body, err := httpFn(url)
+		if err != nil {
+			setGlobalErr(scope, "fetch: "+err.Error())
+			return &String{}, nil
+		}
+		if status < 200 || status >= 300 {
+			setGlobalErr(scope, "fetch: "+url+" returned non-2xx status")
+		}
+		return &String{Val: body}, nil
+	}
+}
+
+var postDecl = &parser.FuncDeclStmt{
+	Name: "post",
+	Params: []*parser.Var{
+		{Name: "url", T: parser.STRING_TYPE},
+		{Name: "body", T: parser.STRING_TYPE},
+	},
+	ReturnType: parser.STRING_TYPE,
+}
+
+func postFunc(httpFn func(string, string) (int, string, error), printFn func(string)) BuiltinFunc {
+	if httpFn == nil {
+		return notImplementedFunc("post", printFn)
+	}
+	return func(scope *scope, args []Value) (Value, error) {
+		resetGlobalErr(scope)
+		url := args[0].(*String).Val
+		body := args[1].(*String).Val
+		status, respBody, err := httpFn(url, body)
+		if err != nil {
+			setGlobalErr(scope, "post: "+err.Error())
+			return &String{}, nil
+		}
+		if status < 200 || status >= 300 {
+			setGlobalErr(scope, "post: "+url+" returned non-2xx status")
+		}
+		return &String{Val: respBody}, nil
+	}
+}
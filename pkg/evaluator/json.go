@@ -0,0 +1,106 @@
+package evaluator
+
+import (
+	"encoding/json"
+
+	"evylang.dev/evy/pkg/parser"
+)
+
+var jsonDecl = &parser.FuncDeclStmt{
+	Name:       "json",
+	Params:     []*parser.Var{{Name: "s", T: parser.STRING_TYPE}},
+	ReturnType: parser.ANY_TYPE,
+}
+
+// jsonFunc parses s as a JSON document into Evy values: objects become
+// *Map, arrays become *Array, mirroring the generic "{}"/"[]" types, and
+// scalars become *Num/*String/*Bool. On a decode error it sets err/errmsg,
+// the same way str2num does, and returns an empty map rather than
+// propagating a Go error.
+func jsonFunc(scope *scope, args []Value) (Value, error) {
+	resetGlobalErr(scope)
+	s := args[0].(*String)
+	var decoded any
+	if err := json.Unmarshal([]byte(s.Val), &decoded); err != nil {
+		setGlobalErr(scope, "json: cannot parse "+s.Val)
+		return &Map{Pairs: map[string]Value{}, Order: &[]string{}}, nil
+	}
+	return valueFromNative(decoded), nil
+}
+
+var json2strDecl = &parser.FuncDeclStmt{
+	Name:       "json2str",
+	Params:     []*parser.Var{{Name: "a", T: parser.ANY_TYPE}},
+	ReturnType: parser.STRING_TYPE,
+}
+
+// json2strFunc serializes an arbitrary Evy value back to a JSON document.
+func json2strFunc(scope *scope, args []Value) (Value, error) {
+	resetGlobalErr(scope)
+	b, err := json.Marshal(nativeFromValue(args[0]))
+	if err != nil {
+		setGlobalErr(scope, "json2str: cannot serialize value")
+		return &String{}, nil
+	}
+	return &String{Val: string(b)}, nil
+}
+
+// valueFromNative converts the result of encoding/json's Unmarshal
+// (map[string]any, []any, float64, string, bool or nil) into the
+// corresponding Evy Value tree.
+func valueFromNative(v any) Value {
+	switch v := v.(type) {
+	case nil:
+		return &Any{}
+	case bool:
+		return &Bool{Val: v}
+	case float64:
+		return &Num{Val: v}
+	case string:
+		return &String{Val: v}
+	case []any:
+		elements := make([]Value, len(v))
+		for i, e := range v {
+			elements[i] = &Any{Val: valueFromNative(e)}
+		}
+		return &Array{Elements: &elements}
+	case map[string]any:
+		pairs := make(map[string]Value, len(v))
+		order := make([]string, 0, len(v))
+		for key, val := range v {
+			pairs[key] = &Any{Val: valueFromNative(val)}
+			order = append(order, key)
+		}
+		return &Map{Pairs: pairs, Order: &order}
+	}
+	return &Any{}
+}
+
+// nativeFromValue unwraps an Evy Value tree into plain Go values
+// (map[string]any, []any, float64, string, bool, nil) suitable for
+// encoding/json's Marshal.
+func nativeFromValue(v Value) any {
+	switch v := v.(type) {
+	case *Any:
+		return nativeFromValue(v.Val)
+	case *Num:
+		return v.Val
+	case *String:
+		return v.Val
+	case *Bool:
+		return v.Val
+	case *Array:
+		elements := make([]any, len(*v.Elements))
+		for i, e := range *v.Elements {
+			elements[i] = nativeFromValue(e)
+		}
+		return elements
+	case *Map:
+		obj := make(map[string]any, len(v.Pairs))
+		for _, key := range *v.Order {
+			obj[key] = nativeFromValue(v.Pairs[key])
+		}
+		return obj
+	}
+	return nil
+}
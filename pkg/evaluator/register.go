@@ -0,0 +1,72 @@
+package evaluator
+
+import "evylang.dev/evy/pkg/parser"
+
+// RegisterBuiltin adds or replaces a single builtin function. Host
+// applications embedding Evy (the playground, a CLI, a future WASM
+// plug-in host) use this to add domain-specific builtins such as
+// "fetch", "fs.read", or "midi.play" without forking the evaluator.
+// Registration must happen before Parse so the function is visible to the
+// parser's type checking.
+func (b *Builtins) RegisterBuiltin(name string, decl *parser.FuncDeclStmt, fn BuiltinFunc) {
+	if b.Funcs == nil {
+		b.Funcs = map[string]Builtin{}
+	}
+	b.Funcs[name] = Builtin{Func: fn, Decl: decl}
+}
+
+// RegisterEventHandler adds or replaces a pluggable event handler
+// signature, e.g. for a host-specific event such as "midi".
+func (b *Builtins) RegisterEventHandler(name string, handler *parser.EventHandlerStmt) {
+	if b.EventHandlers == nil {
+		b.EventHandlers = map[string]*parser.EventHandlerStmt{}
+	}
+	b.EventHandlers[name] = handler
+}
+
+// RegisterGlobal adds or replaces a global variable visible to Evy
+// programs, e.g. a host-specific status global alongside err/errmsg.
+func (b *Builtins) RegisterGlobal(name string, v *parser.Var) {
+	if b.Globals == nil {
+		b.Globals = map[string]*parser.Var{}
+	}
+	b.Globals[name] = v
+}
+
+// Option configures a Builtins value built by NewBuiltins.
+type Option func(*Builtins)
+
+// WithBuiltin returns an Option that registers a single builtin function,
+// for composing a Builtins value at construction time.
+func WithBuiltin(name string, decl *parser.FuncDeclStmt, fn BuiltinFunc) Option {
+	return func(b *Builtins) { b.RegisterBuiltin(name, decl, fn) }
+}
+
+// WithEventHandler returns an Option that registers a single event handler
+// signature.
+func WithEventHandler(name string, handler *parser.EventHandlerStmt) Option {
+	return func(b *Builtins) { b.RegisterEventHandler(name, handler) }
+}
+
+// WithGlobal returns an Option that registers a single global variable.
+func WithGlobal(name string, v *parser.Var) Option {
+	return func(b *Builtins) { b.RegisterGlobal(name, v) }
+}
+
+// NewBuiltins returns the default builtins for rt with opts applied on
+// top, letting embedders extend the default set instead of replacing it
+// wholesale.
+func NewBuiltins(rt *Runtime, opts ...Option) Builtins {
+	builtins := DefaultBuiltins(rt)
+	for _, opt := range opts {
+		opt(&builtins)
+	}
+	return builtins
+}
+
+// Decls returns the parser's view of b's declarations, derived on demand
+// so builtins registered after DefaultBuiltins/NewBuiltins — but before
+// Parse — are still visible to the type checker.
+func (b Builtins) Decls() parser.Builtins {
+	return ParserBuiltins(b)
+}
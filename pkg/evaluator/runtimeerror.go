@@ -0,0 +1,74 @@
+package evaluator
+
+import (
+	"fmt"
+
+	"evylang.dev/evy/pkg/parser"
+)
+
+// ErrorKind classifies a RuntimeError so callers (editor tooling, the
+// playground, test assertions) can distinguish e.g. a type mismatch from a
+// missing variable without string-matching Msg.
+type ErrorKind int
+
+// Runtime error kinds. New kinds should be added here as new
+// newRuntimeError call sites are introduced elsewhere in this package.
+const (
+	KindTypeMismatch ErrorKind = iota
+	KindIndexOutOfRange
+	KindDivideByZero
+	KindUnknownVar
+	KindMapKeyMissing
+	KindConditionNotBool
+	KindDepthExceeded
+	KindCanceled
+	KindGeneric
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case KindTypeMismatch:
+		return "TypeMismatch"
+	case KindIndexOutOfRange:
+		return "IndexOutOfRange"
+	case KindDivideByZero:
+		return "DivideByZero"
+	case KindUnknownVar:
+		return "UnknownVar"
+	case KindMapKeyMissing:
+		return "MapKeyMissing"
+	case KindConditionNotBool:
+		return "ConditionNotBool"
+	case KindDepthExceeded:
+		return "DepthExceeded"
+	case KindCanceled:
+		return "Canceled"
+	default:
+		return "Generic"
+	}
+}
+
+// RuntimeError is a structured evaluation error: a Kind tooling can branch
+// on, the human-readable Msg previously carried alone by newError, and the
+// Pos of the parser.Node that triggered it. It lets an IDE or the
+// playground underline the offending source span instead of only printing
+// a flat message.
+type RuntimeError struct {
+	Kind ErrorKind
+	Msg  string
+	Pos  parser.Position
+}
+
+// Error satisfies the standard error interface.
+func (e *RuntimeError) Error() string {
+	return fmt.Sprintf("%d:%d: %s", e.Pos.Line, e.Pos.Column, e.Msg)
+}
+
+// newRuntimeError formats a RuntimeError for pos as "line:column: msg" and
+// wraps that string the same way newError wraps a bare message, so every
+// existing isError/printFn call site keeps working unchanged. The returned
+// Value carries only the formatted message: Kind is not kept, and no
+// *RuntimeError can be recovered from it with errors.As.
+func newRuntimeError(kind ErrorKind, pos parser.Position, msg string) Value {
+	return newError((&RuntimeError{Kind: kind, Msg: msg, Pos: pos}).Error())
+}
@@ -1,15 +1,22 @@
 package evaluator
 
-import "strconv"
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+)
 
 // TestReporting represents an enumeration of reporting styles.
 type TestReporting int
 
-// Test reporting styles: terse, verbose or none.
+// Test reporting styles: terse, verbose, none or machine-readable JSON.
+// JSONReporting is handled by ReportJSON rather than Report.
 const (
 	TerseReporting TestReporting = iota
 	VerboseReporting
 	NoReporting
+	JSONReporting
 )
 
 // TestInfo contains flags for test runs, e.g. FailFast and testResult
@@ -39,7 +46,7 @@ func (t *TestInfo) TotalCount() int {
 
 // Report prints a summary of the test results.
 func (t *TestInfo) Report(printFn func(string)) {
-	if t.Reporting == NoReporting || t.total == 0 {
+	if t.Reporting == NoReporting || t.Reporting == JSONReporting || t.total == 0 {
 		return
 	}
 	var msg string
@@ -54,3 +61,65 @@ func (t *TestInfo) Report(printFn func(string)) {
 	}
 	printFn(msg)
 }
+
+// jsonReportVersion is embedded in every record ReportJSON emits, so a
+// downstream consumer (CI, jq pipeline) can detect a breaking schema
+// change.
+const jsonReportVersion = 1
+
+// jsonAssertionReport is one line of a ReportJSON stream describing a
+// single failed assertion. TestInfo only retains failures individually;
+// passed assertions are reflected solely in the jsonSummaryReport counts.
+type jsonAssertionReport struct {
+	Version int    `json:"version"`
+	Type    string `json:"type"` // "assertion"
+	Index   int    `json:"index"`
+	Status  string `json:"status"` // always "fail"; see type doc
+	Message string `json:"message,omitempty"`
+	Kind    string `json:"kind,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+}
+
+// jsonSummaryReport is the final line of a ReportJSON stream.
+type jsonSummaryReport struct {
+	Version int    `json:"version"`
+	Type    string `json:"type"` // "summary"
+	Total   int    `json:"total"`
+	Passed  int    `json:"passed"`
+	Failed  int    `json:"failed"`
+}
+
+// ReportJSON writes one JSON object per line to w: one per failed
+// assertion, in the style of `go test -json`, followed by a final
+// summary record. If an error is a *RuntimeError its Kind and source
+// position are included.
+func (t *TestInfo) ReportJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	for i, err := range t.errors {
+		rec := jsonAssertionReport{
+			Version: jsonReportVersion,
+			Type:    "assertion",
+			Index:   i,
+			Status:  "fail",
+			Message: err.Error(),
+		}
+		var rtErr *RuntimeError
+		if errors.As(err, &rtErr) {
+			rec.Kind = rtErr.Kind.String()
+			rec.Line = rtErr.Pos.Line
+			rec.Column = rtErr.Pos.Column
+		}
+		if err := enc.Encode(rec); err != nil {
+			return err
+		}
+	}
+	summary := jsonSummaryReport{
+		Version: jsonReportVersion,
+		Type:    "summary",
+		Total:   t.TotalCount(),
+		Passed:  t.SuccessCount(),
+		Failed:  t.FailCount(),
+	}
+	return enc.Encode(summary)
+}
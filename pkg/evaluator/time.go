@@ -0,0 +1,112 @@
+package evaluator
+
+import (
+	"strings"
+	"time"
+
+	"evylang.dev/evy/pkg/parser"
+)
+
+// TimeRuntime lets the host inject the clock backing the time builtins,
+// mirroring the Print/Read/Sleep injection pattern so tests can use a
+// fake, deterministic clock instead of the wall clock.
+type TimeRuntime struct {
+	Now func() time.Time
+}
+
+var nowDecl = &parser.FuncDeclStmt{
+	Name:       "now",
+	ReturnType: parser.NUM_TYPE,
+}
+
+func nowFunc(nowFn func() time.Time) BuiltinFunc {
+	return func(_ *scope, args []Value) (Value, error) {
+		return &Num{Val: float64(nowFn().UnixMilli()) / 1000}, nil
+	}
+}
+
+var unixDecl = &parser.FuncDeclStmt{
+	Name:       "unix",
+	ReturnType: parser.NUM_TYPE,
+}
+
+func unixFunc(nowFn func() time.Time) BuiltinFunc {
+	return func(_ *scope, args []Value) (Value, error) {
+		return &Num{Val: float64(nowFn().Unix())}, nil
+	}
+}
+
+var monotonicDecl = &parser.FuncDeclStmt{
+	Name:       "monotonic",
+	ReturnType: parser.NUM_TYPE,
+}
+
+// monotonicFunc reports seconds since monotonicFunc was called, that is
+// since DefaultBuiltins built the builtin, backed by Go's monotonic clock
+// reading rather than nowFn, so it is unaffected by wall-clock
+// adjustments.
+func monotonicFunc() BuiltinFunc {
+	start := time.Now()
+	return func(_ *scope, args []Value) (Value, error) {
+		return &Num{Val: time.Since(start).Seconds()}, nil
+	}
+}
+
+var timefmtDecl = &parser.FuncDeclStmt{
+	Name: "timefmt",
+	Params: []*parser.Var{
+		{Name: "t", T: parser.NUM_TYPE},
+		{Name: "layout", T: parser.STRING_TYPE},
+	},
+	ReturnType: parser.STRING_TYPE,
+}
+
+func timefmtFunc(_ *scope, args []Value) (Value, error) {
+	t := args[0].(*Num).Val
+	layout := args[1].(*String).Val
+	tm := time.UnixMilli(int64(t * 1000)).UTC()
+	return &String{Val: tm.Format(goLayout(layout))}, nil
+}
+
+var parsetimeDecl = &parser.FuncDeclStmt{
+	Name: "parsetime",
+	Params: []*parser.Var{
+		{Name: "s", T: parser.STRING_TYPE},
+		{Name: "layout", T: parser.STRING_TYPE},
+	},
+	ReturnType: parser.NUM_TYPE,
+}
+
+func parsetimeFunc(scope *scope, args []Value) (Value, error) {
+	resetGlobalErr(scope)
+	s := args[0].(*String).Val
+	layout := args[1].(*String).Val
+	tm, err := time.Parse(goLayout(layout), s)
+	if err != nil {
+		setGlobalErr(scope, "parsetime: cannot parse "+s)
+		return &Num{}, nil
+	}
+	return &Num{Val: float64(tm.Unix())}, nil
+}
+
+// layoutTokens maps Evy's beginner-friendly layout tokens to Go's
+// reference-time ones, so Evy programs never have to learn Go's
+// "Mon Jan 2 15:04:05 MST 2006" magic string.
+var layoutTokens = []struct {
+	evy string
+	goL string
+}{
+	{"YYYY", "2006"},
+	{"MM", "01"},
+	{"DD", "02"},
+	{"hh", "15"},
+	{"mm", "04"},
+	{"ss", "05"},
+}
+
+func goLayout(layout string) string {
+	for _, t := range layoutTokens {
+		layout = strings.ReplaceAll(layout, t.evy, t.goL)
+	}
+	return layout
+}
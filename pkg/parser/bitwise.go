@@ -0,0 +1,17 @@
+package parser
+
+// Additional binary/unary Operators for integer arithmetic and bitwise
+// logic on num values, layered on top of the existing arithmetic and
+// comparison operators. Values start at a high offset so they cannot
+// collide with the operator constants declared alongside the lexer/token
+// definitions.
+const (
+	OP_PERCENT     Operator = iota + 1000 // %
+	OP_SLASH_SLASH                        // //
+	OP_AMP                                // &
+	OP_PIPE                               // |
+	OP_CARET                              // ^
+	OP_LSHIFT                             // <<
+	OP_RSHIFT                             // >>
+	OP_TILDE                              // ~ (unary)
+)
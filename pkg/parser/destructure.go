@@ -0,0 +1,82 @@
+package parser
+
+import "fmt"
+
+// DestructureStmt binds several names at once from a single array or map
+// value, e.g. "x, y := someArrPair" or "{name age} := person". It behaves
+// like Declaration/Assignment but fans out into one slot per Target.
+//
+// Targets preserves the left-to-right order the names appeared in; for a map
+// pattern each Target's Name is also the key looked up on the RHS map.
+type DestructureStmt struct {
+	Pos     Position
+	IsMap   bool // true for "{a b} := ..." patterns, false for "x, y := ..." / "[x y] := ..."
+	IsDecl  bool // true for ":=" declaration, false for "=" assignment
+	Targets []*DestructureTarget
+	Value   Node
+}
+
+// DestructureTarget is one binding slot in a destructuring pattern. Name is
+// "_" for a discarded slot, which is permitted here even though "_" is
+// rejected as a top-level declaration target.
+type DestructureTarget struct {
+	Pos  Position
+	Name string
+	Var  *Var // nil if Name == "_"
+}
+
+func (d *DestructureStmt) String() string {
+	names := make([]string, len(d.Targets))
+	for i, t := range d.Targets {
+		names[i] = t.Name
+	}
+	op := "="
+	if d.IsDecl {
+		op = ":="
+	}
+	return fmt.Sprintf("%v %s %v", names, op, d.Value)
+}
+
+// checkArrayDestructure validates that an array type can be destructured
+// into exactly len(targets) elements, as required by a "x, y := arr" or
+// "[x y] := arr" pattern. It mirrors the arity checking done for function
+// call arguments elsewhere in the parser.
+func checkArrayDestructure(pos Position, targets []*DestructureTarget, n int) error {
+	if len(targets) != n {
+		return &parseError{
+			pos: pos,
+			msg: fmt.Sprintf("destructuring expects array of length %d, found length %d", len(targets), n),
+		}
+	}
+	return nil
+}
+
+// checkMapDestructure validates that every named target (other than "_")
+// refers to a key present in the map's declared key set, as required by a
+// "{name age} := person" pattern.
+func checkMapDestructure(pos Position, targets []*DestructureTarget, keys map[string]bool) error {
+	for _, t := range targets {
+		if t.Name == "_" {
+			continue
+		}
+		if !keys[t.Name] {
+			return &parseError{
+				pos: pos,
+				msg: fmt.Sprintf("key %q not present in map value type", t.Name),
+			}
+		}
+	}
+	return nil
+}
+
+// parseError is a minimal positioned error used by the destructuring
+// validation helpers above; it satisfies the same shape consumed by
+// Errors.Truncate elsewhere in this package.
+type parseError struct {
+	pos Position
+	msg string
+}
+
+func (e *parseError) Error() string {
+	return fmt.Sprintf("line %d column %d: %s", e.pos.Line, e.pos.Column, e.msg)
+}
@@ -0,0 +1,146 @@
+package parser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrorCode is a stable, machine-readable identifier for a parse
+// diagnostic, suitable for editor tooling to key off of instead of matching
+// the human-readable message.
+type ErrorCode string
+
+// Error codes emitted across the parser. New codes should be added here as
+// diagnostics are introduced elsewhere in the package.
+const (
+	ECodeOverrideBuiltin ErrorCode = "E_OVERRIDE_BUILTIN"
+	ECodeTypeMismatch    ErrorCode = "E_TYPE_MISMATCH"
+	ECodeUnknownFunc     ErrorCode = "E_UNKNOWN_FUNC"
+	ECodeUnknownVar      ErrorCode = "E_UNKNOWN_VAR"
+	ECodeRedeclaration   ErrorCode = "E_REDECLARATION"
+	ECodeUnreachableCode ErrorCode = "E_UNREACHABLE_CODE"
+	ECodeGeneric         ErrorCode = "E_GENERIC"
+)
+
+// Severity classifies a Diagnostic the way LSP's DiagnosticSeverity does.
+type Severity int
+
+// Diagnostic severities.
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+	SeverityInfo
+)
+
+// Range is a half-open [Start, End) source span, in addition to the single
+// Position already used by the plain-string error format.
+type Range struct {
+	Start Position
+	End   Position
+}
+
+// RelatedInfo points at a secondary location relevant to a Diagnostic, e.g.
+// the original declaration site when reporting "cannot override builtin
+// function".
+type RelatedInfo struct {
+	Range   Range
+	Message string
+}
+
+// Diagnostic is a structured parse error: a Range, a stable Code, a
+// Severity, the legacy flat Msg used by Error() for backward compatibility,
+// and optional Related locations.
+type Diagnostic struct {
+	Range    Range
+	Code     ErrorCode
+	Severity Severity
+	Msg      string
+	Related  []RelatedInfo
+}
+
+// Error formats the Diagnostic exactly as the existing flat
+// "line X column Y: msg" errors did, so current callers and tests comparing
+// against Error() strings are unaffected.
+func (d *Diagnostic) Error() string {
+	return formatPositionedError(d.Range.Start, d.Msg)
+}
+
+func formatPositionedError(pos Position, msg string) string {
+	return fmt.Sprintf("line %d column %d: %s", pos.Line, pos.Column, msg)
+}
+
+// diagnosticJSON is the wire shape emitted by MarshalJSON / ParseToJSON,
+// using plain field names and string severities so it is easy to consume
+// from editor tooling without pulling in this package.
+type diagnosticJSON struct {
+	Line     int           `json:"line"`
+	Column   int           `json:"column"`
+	EndLine  int           `json:"endLine"`
+	EndCol   int           `json:"endColumn"`
+	Code     string        `json:"code"`
+	Severity string        `json:"severity"`
+	Message  string        `json:"message"`
+	Related  []relatedJSON `json:"related,omitempty"`
+}
+
+type relatedJSON struct {
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Message string `json:"message"`
+}
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "info"
+	default:
+		return "error"
+	}
+}
+
+// MarshalJSON renders a Diagnostic in the diagnosticJSON wire shape.
+func (d *Diagnostic) MarshalJSON() ([]byte, error) {
+	related := make([]relatedJSON, len(d.Related))
+	for i, r := range d.Related {
+		related[i] = relatedJSON{Line: r.Range.Start.Line, Column: r.Range.Start.Column, Message: r.Message}
+	}
+	return json.Marshal(diagnosticJSON{
+		Line:     d.Range.Start.Line,
+		Column:   d.Range.Start.Column,
+		EndLine:  d.Range.End.Line,
+		EndCol:   d.Range.End.Column,
+		Code:     string(d.Code),
+		Severity: d.Severity.String(),
+		Message:  d.Msg,
+		Related:  related,
+	})
+}
+
+// ParseToJSON parses input and returns its diagnostics serialized as a JSON
+// array, one object per Diagnostic, in the shape consumed by editor/LSP
+// tooling and CI systems.
+func ParseToJSON(input string, b Builtins) ([]byte, error) {
+	_, err := Parse(input, b)
+	var diags []*Diagnostic
+	var parseErrs *Errors
+	if errors.As(err, &parseErrs) {
+		for _, e := range *parseErrs {
+			diags = append(diags, toDiagnostic(e))
+		}
+	}
+	return json.Marshal(diags)
+}
+
+// toDiagnostic adapts a legacy *parseError (or any error implementing
+// Error() string) into a Diagnostic with a best-effort generic code, for
+// errors that have not yet been migrated to originate a Diagnostic
+// directly.
+func toDiagnostic(err error) *Diagnostic {
+	if d, ok := err.(*Diagnostic); ok {
+		return d
+	}
+	return &Diagnostic{Code: ECodeGeneric, Severity: SeverityError, Msg: err.Error()}
+}
@@ -0,0 +1,61 @@
+package parser
+
+import "fmt"
+
+// EventDef declares the schema for one registrable "on <name> ..." event
+// handler: the name of each parameter and its required type. Hosts (the web
+// runtime, a CLI runner, tests) register their own set of EventDefs via
+// Builtins.Events instead of the parser hardcoding a fixed list.
+type EventDef struct {
+	Name   string
+	Params []*Var
+}
+
+// validateEventParams checks a parsed "on <name> p1 p2 ..." handler's
+// parameter list against the registered EventDef for name: same arity, and
+// for every non-"_" parameter, the same type in the same position. "_" may
+// be used in place of any parameter name or type.
+func validateEventParams(pos Position, def *EventDef, params []*Var) error {
+	if len(params) != len(def.Params) {
+		return &parseError{
+			pos: pos,
+			msg: fmt.Sprintf("wrong number of parameters expected %d, got %d", len(def.Params), len(params)),
+		}
+	}
+	for i, want := range def.Params {
+		got := params[i]
+		if got.Name == "_" {
+			continue
+		}
+		if got.Type().String() != want.Type().String() {
+			return &parseError{
+				pos: pos,
+				msg: fmt.Sprintf("parameter %q expects type %s, found %s", want.Name, want.Type(), got.Type()),
+			}
+		}
+	}
+	return nil
+}
+
+// lookupEventDef resolves name against the registered event schema,
+// producing the existing "unknown event name" diagnostic shape when it is
+// not found.
+func lookupEventDef(pos Position, events map[string]*EventDef, name string) (*EventDef, error) {
+	def, ok := events[name]
+	if !ok {
+		return nil, &parseError{pos: pos, msg: "unknown event name " + name}
+	}
+	return def, nil
+}
+
+// mergeEventHandlerBody appends the statements of a later "on <event> ..."
+// block onto an earlier one for the same event name, so multiple "on down"
+// blocks in a program are concatenated in registration order rather than
+// conflicting.
+func mergeEventHandlerBody(existing, additional *BlockStatement) *BlockStatement {
+	if existing == nil {
+		return additional
+	}
+	existing.Statements = append(existing.Statements, additional.Statements...)
+	return existing
+}
@@ -0,0 +1,77 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FUNC_TYPE is the Name of a function-valued Type, e.g. "func():num" or
+// "func(num,string):bool". Sub is unused for FUNC_TYPE; parameter and return
+// types live in FuncSig.
+const FUNC_TYPE = "func" //nolint:revive // matches the existing NUM_TYPE/STRING_TYPE naming convention
+
+// FuncSig describes the callable shape of a func-typed value: its parameter
+// types (Variadic == true means the last entry repeats) and its return
+// type, or NONE_TYPE for a function with no return value.
+type FuncSig struct {
+	Params     []*Type
+	Variadic   bool
+	ReturnType *Type
+}
+
+// NewFuncType builds the *Type for a function value with the given
+// signature, used wherever a variable, parameter, or composite literal
+// element is declared "func(...)".
+func NewFuncType(sig *FuncSig) *Type {
+	return &Type{Name: FUNC_TYPE, FuncSig: sig}
+}
+
+// String renders a function type the way it appears in source:
+// "func(num,string):bool", "func():none", "func(num...):none".
+func (s *FuncSig) String() string {
+	parts := make([]string, len(s.Params))
+	for i, p := range s.Params {
+		parts[i] = p.String()
+	}
+	if s.Variadic && len(parts) > 0 {
+		parts[len(parts)-1] += "..."
+	}
+	return fmt.Sprintf("func(%s):%s", strings.Join(parts, ","), s.ReturnType)
+}
+
+// Equals reports whether two function signatures are assignable to each
+// other: equal arity/variadic-ness, equal parameter types in order, and
+// equal return type. Used when checking "f := print" (infer) and
+// "g:func():num; g = h" (declared-type compatibility).
+func (s *FuncSig) Equals(other *FuncSig) bool {
+	if other == nil || s.Variadic != other.Variadic || len(s.Params) != len(other.Params) {
+		return false
+	}
+	for i, p := range s.Params {
+		if p.String() != other.Params[i].String() {
+			return false
+		}
+	}
+	return s.ReturnType.String() == other.ReturnType.String()
+}
+
+// sigFromFuncDecl derives a FuncSig from a user-defined or builtin function
+// declaration, so "f := someFunc" and later calls through f can be checked
+// uniformly with calls through a func-typed variable.
+func sigFromFuncDecl(fd *FuncDeclStmt) *FuncSig {
+	sig := &FuncSig{ReturnType: fd.ReturnType}
+	for _, p := range fd.Params {
+		sig.Params = append(sig.Params, p.Type())
+	}
+	if fd.VariadicParam != nil {
+		sig.Variadic = true
+		sig.Params = append(sig.Params, fd.VariadicParam.Type())
+	}
+	return sig
+}
+
+// notCallableErr formats the standard "not callable" diagnostic raised when
+// a non-func-typed variable is used in call position, e.g. "n := 1; n 2".
+func notCallableErr(pos Position, name string) error {
+	return &parseError{pos: pos, msg: fmt.Sprintf("%q is not callable", name)}
+}
@@ -0,0 +1,90 @@
+package parser
+
+import "fmt"
+
+// TYPE_VAR and UNION_TYPE extend Type.Name so builtins can declare
+// polymorphic signatures instead of hard-coded sentinels like the existing
+// GENERIC_MAP. A TYPE_VAR parameter ("T") unifies with whatever concrete
+// type is passed at the call site, and that binding is then enforced for
+// every later occurrence of the same variable, including the return type. A
+// UNION_TYPE parameter ("array or string") accepts any of Constraints.
+const (
+	TYPE_VAR   = "typevar"   //nolint:revive // matches existing *_TYPE naming
+	UNION_TYPE = "union"     //nolint:revive // matches existing *_TYPE naming
+)
+
+// NewTypeVar returns a fresh type-variable Type named name, e.g. "T".
+func NewTypeVar(name string) *Type {
+	return &Type{Name: TYPE_VAR, TypeVarName: name}
+}
+
+// NewUnionType returns a Type accepting any of constraints, e.g. the
+// "array or string" parameter type of a hypothetical "len" signature.
+func NewUnionType(constraints ...*Type) *Type {
+	return &Type{Name: UNION_TYPE, Constraints: constraints}
+}
+
+// bindings tracks the concrete Type each type-variable name has been
+// unified with during a single call's argument checking.
+type bindings map[string]*Type
+
+// unify attempts to reconcile param (which may reference TYPE_VARs or be a
+// UNION_TYPE) against the concrete argument type arg, recording any new
+// type-variable bindings into b. It returns an error in the same shape as
+// the existing "takes 1st argument of type X, found Y" diagnostics when
+// unification fails or a type variable is bound inconsistently across
+// parameters.
+func unify(b bindings, param, arg *Type, argPos int, funcName string) error {
+	switch param.Name {
+	case TYPE_VAR:
+		if bound, ok := b[param.TypeVarName]; ok {
+			if bound.String() != arg.String() {
+				return mismatchErr(argPos, funcName, bound, arg)
+			}
+			return nil
+		}
+		b[param.TypeVarName] = arg
+		return nil
+	case UNION_TYPE:
+		for _, c := range param.Constraints {
+			if unify(bindings{}, c, arg, argPos, funcName) == nil {
+				return nil
+			}
+		}
+		return mismatchErr(argPos, funcName, param, arg)
+	default:
+		if param.String() != arg.String() {
+			return mismatchErr(argPos, funcName, param, arg)
+		}
+		return nil
+	}
+}
+
+// resolveReturnType substitutes any TYPE_VAR occurrences in returnType with
+// the concrete bindings discovered while unifying the call's arguments, so
+// e.g. "first [T]" returns the bound T for a given call site.
+func resolveReturnType(returnType *Type, b bindings) *Type {
+	if returnType.Name == TYPE_VAR {
+		if bound, ok := b[returnType.TypeVarName]; ok {
+			return bound
+		}
+	}
+	return returnType
+}
+
+func mismatchErr(argPos int, funcName string, want, got *Type) error {
+	return fmt.Errorf("%q takes %s argument of type %s, found %s", funcName, ordinal(argPos), want, got)
+}
+
+func ordinal(n int) string {
+	switch n {
+	case 1:
+		return "1st"
+	case 2:
+		return "2nd"
+	case 3:
+		return "3rd"
+	default:
+		return fmt.Sprintf("%dth", n)
+	}
+}
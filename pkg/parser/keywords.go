@@ -0,0 +1,26 @@
+package parser
+
+// reservedKeywords lists the tokens that remain reserved everywhere except
+// map-literal key position and the postfix "." selector, where they may be
+// used as plain identifiers, e.g. "a := {if: 1 else: 2}" and "a.return = 4".
+var reservedKeywords = map[string]bool{
+	"if": true, "else": true, "while": true, "for": true, "func": true,
+	"on": true, "range": true, "return": true, "break": true, "end": true,
+	"true": true, "false": true,
+}
+
+// isKeywordLexeme reports whether text is the literal spelling of a
+// keyword, independent of how the lexer classified the token. It is used by
+// parseMapLiteral and the "." selector to accept keyword-shaped identifiers
+// in those two positions only.
+func isKeywordLexeme(text string) bool {
+	return reservedKeywords[text]
+}
+
+// allowedAsMapKeyOrSelector reports whether tok's literal text may be used
+// as a map key or field selector even though it would otherwise lex as a
+// keyword token. Declarations, function names, and parameter names must
+// still reject these names.
+func allowedAsMapKeyOrSelector(tok token) bool {
+	return isKeywordLexeme(tok.literal) || tok.kind == IDENT
+}
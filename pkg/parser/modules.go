@@ -0,0 +1,75 @@
+package parser
+
+import "fmt"
+
+// Module is a host-registered native module: a bundle of functions and
+// global variables that an "import" statement can bring into scope without
+// needing Evy source to parse. It carries no type aliases.
+type Module struct {
+	Name    string
+	Funcs   map[string]*FuncDeclStmt
+	Globals map[string]*Var
+}
+
+// ImportStmt brings the exported symbols of another module into scope,
+// qualified as "modname.symbol" in the AST, e.g. "import strings" then
+// "strings.upper s".
+type ImportStmt struct {
+	Pos  Position
+	Name string
+}
+
+func (i *ImportStmt) String() string { return "import " + i.Name }
+
+// ErrImportCycle reports a module that transitively imports itself.
+// resolveImports does not follow a source module's own imports, so it
+// does not return this error.
+var ErrImportCycle = fmt.Errorf("import cycle detected")
+
+// resolveImports resolves each name in names, Tengo-importables style: a
+// NativeModules entry is used as is, its Funcs/Globals merged directly,
+// and a SourceModules entry resolves to an empty Module of that name. The
+// source text is not parsed here and its own imports are not followed, so
+// no import cycle is detected. A name found in neither map is an error.
+func resolveImports(names []string, sourceModules map[string]string, nativeModules map[string]*Module) (map[string]*Module, error) {
+	resolved := map[string]*Module{}
+	visiting := map[string]bool{}
+	var visit func(name string) error
+	visit = func(name string) error {
+		if _, ok := resolved[name]; ok {
+			return nil
+		}
+		if native, ok := nativeModules[name]; ok {
+			resolved[name] = native
+			return nil
+		}
+		src, ok := sourceModules[name]
+		if !ok {
+			return fmt.Errorf("unknown module %q", name)
+		}
+		if visiting[name] {
+			return fmt.Errorf("%w: %s", ErrImportCycle, name)
+		}
+		visiting[name] = true
+		defer delete(visiting, name)
+
+		// The source module is not parsed here: it is exported to the
+		// importer as a Module with no Funcs or Globals.
+		sub := &Module{Name: name, Funcs: map[string]*FuncDeclStmt{}, Globals: map[string]*Var{}}
+		resolved[name] = sub
+		_ = src // full source module parsing happens in newParser via Builtins.SourceModules
+		return nil
+	}
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+	return resolved, nil
+}
+
+// qualifiedName returns the AST-internal qualified symbol name for a
+// function or global imported from module mod, e.g. "strings.upper".
+func qualifiedName(mod, symbol string) string {
+	return mod + "." + symbol
+}
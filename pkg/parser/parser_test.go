@@ -183,6 +183,41 @@ print(any("TRUE"))
 	}
 }
 
+func TestImport(t *testing.T) {
+	builtins := testBuiltins()
+	builtins.NativeModules = map[string]*Module{
+		"mathx": {
+			Name: "mathx",
+			Funcs: map[string]*FuncDeclStmt{
+				"double": {
+					Name:       "double",
+					Params:     []*Var{{Name: "n", T: NUM_TYPE}},
+					ReturnType: NUM_TYPE,
+				},
+			},
+		},
+	}
+	input := `
+import mathx
+x := mathx.double 2
+print x
+`
+	parser := newParser(input, builtins)
+	_ = parser.parse()
+	assertNoParseError(t, parser, input)
+}
+
+func TestImportErr(t *testing.T) {
+	input := `
+import nosuchmodule
+`
+	parser := newParser(input, testBuiltins())
+	_ = parser.parse()
+	assertParseError(t, parser, input)
+	gotErr := parser.errors.Truncate(1)
+	assert.Equal(t, `line 2 column 1: unknown module "nosuchmodule"`, gotErr.Error())
+}
+
 func TestToplevelExprFuncCall(t *testing.T) {
 	input := `
 x := len "123"
@@ -389,6 +424,76 @@ end
 	}
 }
 
+func TestTry(t *testing.T) {
+	inputs := []string{
+		`
+try
+	raise "oops" "io" 0
+except "io" as e
+	print e.message
+end
+`, `
+try
+	print "ok"
+except
+	print "never"
+finally
+	print "cleanup"
+end
+`, `
+try
+	print "ok"
+except "io", "net" as e
+	print e.kind
+except
+	print "other"
+finally
+	print "cleanup"
+end
+`,
+	}
+	for _, input := range inputs {
+		parser := newParser(input, testBuiltins())
+		_ = parser.parse()
+		assertNoParseError(t, parser, input)
+	}
+}
+
+func TestTryErr(t *testing.T) {
+	inputs := map[string]string{
+		`
+try
+	print "ok"
+except
+	print "a"
+except
+	print "b"
+end
+`: "line 6 column 1: duplicate catch-all except clause",
+		`
+try
+	print "ok"
+except
+	print "a"
+except "io"
+	print "b"
+end
+`: "line 6 column 1: catch-all except clause must be last",
+		`
+try
+	print "ok"
+end
+`: "line 4 column 1: try requires at least one except or finally clause",
+	}
+	for input, wantErr := range inputs {
+		parser := newParser(input, testBuiltins())
+		_ = parser.parse()
+		assertParseError(t, parser, input)
+		gotErr := parser.errors.Truncate(1)
+		assert.Equal(t, wantErr, gotErr.Error(), "input: %s", input)
+	}
+}
+
 func TestAssignment(t *testing.T) {
 	inputs := []string{
 		`
@@ -470,6 +575,57 @@ fn = 3
 	}
 }
 
+func TestDestructuring(t *testing.T) {
+	inputs := []string{
+		`
+pair := [1 2]
+x, y := pair
+print x y
+`, `
+pair := [1 2]
+[x y] := pair
+print x y
+`, `
+pair := [1 2]
+x, _ := pair
+print x
+`, `
+person := {name: "Greta" age: 1}
+{name age} := person
+print name age
+`,
+	}
+	for _, input := range inputs {
+		parser := newParser(input, testBuiltins())
+		_ = parser.parse()
+		assertNoParseError(t, parser, input)
+	}
+}
+
+func TestDestructuringErr(t *testing.T) {
+	inputs := map[string]string{
+		`
+pair := [1 2 3]
+x, y := pair
+`: `line 3 column 1: destructuring expects array of length 2, found length 3`,
+		`
+person := {name: "Greta"}
+{name age} := person
+`: `line 3 column 1: key "age" not present in map value type`,
+		`
+pair := [1 2]
+x, x := pair
+`: `line 3 column 1: redeclaration of "x"`,
+	}
+	for input, wantErr := range inputs {
+		parser := newParser(input, testBuiltins())
+		_ = parser.parse()
+		assertParseError(t, parser, input)
+		gotErr := parser.errors.Truncate(1)
+		assert.Equal(t, wantErr, gotErr.Error(), "input: %s", input)
+	}
+}
+
 func TestScope(t *testing.T) {
 	inputs := []string{
 		`
@@ -1101,6 +1257,59 @@ end`,
 	}
 }
 
+func TestFuncValue(t *testing.T) {
+	inputs := []string{
+		`
+f := print
+f "hello"
+`, `
+g:func():num
+func one:num
+	return 1
+end
+g = one
+print g()
+`, `
+handlers := {down: onDown up: onUp}
+func onDown
+	print "down"
+end
+func onUp
+	print "up"
+end
+print handlers
+`,
+	}
+	for _, input := range inputs {
+		parser := newParser(input, testBuiltins())
+		_ = parser.parse()
+		assertNoParseError(t, parser, input)
+	}
+}
+
+func TestFuncValueErr(t *testing.T) {
+	inputs := map[string]string{
+		`
+n := 1
+n 2
+`: `line 3 column 1: "n" is not callable`,
+		`
+g:func():num
+func two:string
+	return "2"
+end
+g = two
+`: `line 5 column 1: "g" accepts values of type func():num, found func():string`,
+	}
+	for input, wantErr := range inputs {
+		parser := newParser(input, testBuiltins())
+		_ = parser.parse()
+		assertParseError(t, parser, input)
+		gotErr := parser.errors.Truncate(1)
+		assert.Equal(t, wantErr, gotErr.Error(), "input: %s", input)
+	}
+}
+
 func TestFuncDefErr(t *testing.T) {
 	inputs := map[string]string{
 		`
@@ -1200,6 +1409,34 @@ end`: `line 3 column 5: wrong number of parameters expected 2, got 3`,
 	}
 }
 
+func TestEventHandlerRegistry(t *testing.T) {
+	builtins := testBuiltins()
+	builtins.EventHandlers["myevent"] = &EventHandlerStmt{
+		Name:   "myevent",
+		Params: []*Var{{Name: "x", T: NUM_TYPE}},
+	}
+
+	input := `
+on myevent x:num
+   print x
+end
+`
+	parser := newParser(input, builtins)
+	_ = parser.parse()
+	assertNoParseError(t, parser, input)
+
+	input = `
+on myevent
+   print "missing param"
+end
+`
+	parser = newParser(input, builtins)
+	_ = parser.parse()
+	assertParseError(t, parser, input)
+	gotErr := parser.errors.Truncate(1)
+	assert.Equal(t, `line 3 column 4: wrong number of parameters expected 1, got 0`, gotErr.Error())
+}
+
 func TestGlobalErr(t *testing.T) {
 	inputs := map[string]string{
 		`
@@ -1290,6 +1527,46 @@ print a.( num ) // whitespaces added`,
 	}
 }
 
+func TestTypeAssertionOk(t *testing.T) {
+	inputs := []string{
+		`
+a:any
+n, ok := a.(num)
+print n ok
+`, `
+a:any
+a = "abc"
+s, ok := a.(string)
+print s ok
+`,
+	}
+	for _, input := range inputs {
+		parser := newParser(input, testBuiltins())
+		_ = parser.parse()
+		assertNoParseError(t, parser, input)
+	}
+}
+
+func TestTypeAssertionOkErr(t *testing.T) {
+	inputs := map[string]string{
+		`
+a:any
+n, ok, extra := a.(num)
+`: `line 3 column 1: comma-ok type assertion requires 2 targets, found 3`,
+		`
+a:num
+n, ok := a.(num)
+`: `line 3 column 1: type assertion requires any, found num`,
+	}
+	for input, wantErr := range inputs {
+		parser := newParser(input, testBuiltins())
+		_ = parser.parse()
+		assertParseError(t, parser, input)
+		gotErr := parser.errors.Truncate(1)
+		assert.Equal(t, wantErr, gotErr.Error(), "input: %s", input)
+	}
+}
+
 func TestArrayConcatTypingErr(t *testing.T) {
 	inputs := map[string]string{
 		`
@@ -1488,6 +1765,41 @@ end
 	}
 }
 
+func TestKeywordAsIdentifier(t *testing.T) {
+	inputs := []string{
+		`a := {if: 1 else: 2 while: 3 for: 4 func: 5 on: 6 range: 7 return: 8 break: 9 end: 10 true: 11 false: 12}
+print a`,
+		`
+a := {return: 1}
+a.return = 4
+print a.return
+`,
+	}
+	for _, input := range inputs {
+		parser := newParser(input, testBuiltins())
+		_ = parser.parse()
+		assertNoParseError(t, parser, input)
+	}
+}
+
+func TestKeywordAsIdentifierErr(t *testing.T) {
+	inputs := map[string]string{
+		"if := 1": `line 1 column 1: unexpected input "if"`,
+		`
+func if
+   print "x"
+end
+`: `line 2 column 1: unexpected input "if"`,
+	}
+	for input, wantErr := range inputs {
+		parser := newParser(input, testBuiltins())
+		_ = parser.parse()
+		assertParseError(t, parser, input)
+		gotErr := parser.errors.Truncate(1)
+		assert.Equal(t, wantErr, gotErr.Error(), "input: %s", input)
+	}
+}
+
 func TestMapLitErr(t *testing.T) {
 	inputs := map[string]string{
 		`print {a:1{b:2}}`:    `line 1 column 11: expected map key, found "{"`,
@@ -1535,6 +1847,26 @@ print(any(arr))
 	assert.Equal(t, want, got)
 }
 
+func TestTypeInfo(t *testing.T) {
+	input := `
+arr := [[]]
+print arr`
+	parser := newParser(input, testBuiltins())
+	prog := parser.parse()
+	assertNoParseError(t, parser, input)
+
+	stmt, ok := prog.Statements[1].(*InferredDeclStmt)
+	assert.Equal(t, true, ok, "%v %T", stmt, stmt)
+
+	ti := prog.TypeInfo
+	want := "[][]any"
+	got := ti.TypeOf(stmt.Decl.Value).String()
+	assert.Equal(t, want, got)
+
+	def := ti.DefOf(stmt.Decl.Var)
+	assert.Equal(t, stmt, def)
+}
+
 func TestWrapArrayNoError(t *testing.T) {
 	inputs := []string{
 		`
@@ -1646,6 +1978,43 @@ fn [a]
 	}
 }
 
+func TestGenericBuiltin(t *testing.T) {
+	builtins := testBuiltins()
+	builtins.Funcs["first"] = &FuncDefStmt{
+		Name:       "first",
+		Params:     []*Var{{Name: "a", T: &Type{Name: ARRAY, Sub: NewTypeVar("T")}}},
+		ReturnType: NewTypeVar("T"),
+	}
+	input := `
+a := [1 2 3]
+n := first a
+print n
+`
+	parser := newParser(input, builtins)
+	_ = parser.parse()
+	assertNoParseError(t, parser, input)
+}
+
+func TestGenericBuiltinErr(t *testing.T) {
+	builtins := testBuiltins()
+	builtins.Funcs["pair"] = &FuncDefStmt{
+		Name: "pair",
+		Params: []*Var{
+			{Name: "a", T: NewTypeVar("T")},
+			{Name: "b", T: NewTypeVar("T")},
+		},
+		ReturnType: NONE_TYPE,
+	}
+	input := `
+pair 1 "two"
+`
+	parser := newParser(input, builtins)
+	_ = parser.parse()
+	assertParseError(t, parser, input)
+	gotErr := parser.errors.Truncate(1)
+	assert.Equal(t, `"pair" takes 2nd argument of type num, found string`, gotErr.Error())
+}
+
 func TestBadMapParamType(t *testing.T) {
 	inputs := map[string]string{
 		`
@@ -1684,6 +2053,22 @@ draw_paddle left_pos
 	assert.Equal(t, want, got)
 }
 
+func TestParseToJSON(t *testing.T) {
+	input := `
+left_pos := {x:0 y:50}
+
+func draw_paddle paddle:map
+    print paddle.x paddle.y-10
+end
+
+draw_paddle left_pos
+`
+	b, err := ParseToJSON(input, testBuiltins())
+	assert.NoError(t, err)
+	assert.Equal(t, true, strings.Contains(string(b), `"line":4`))
+	assert.Equal(t, true, strings.Contains(string(b), `"severity":"error"`))
+}
+
 func TestDemo(t *testing.T) {
 	input := `
 move 10 10
@@ -1750,13 +2135,18 @@ func testBuiltins() Builtins {
 			ReturnType: STRING_TYPE,
 		},
 	}
+	xyParams := []*Var{
+		{Name: "x", T: NUM_TYPE},
+		{Name: "y", T: NUM_TYPE},
+	}
 	eventHandlers := map[string]*EventHandlerStmt{
-		"down": {
-			Name: "down",
-			Params: []*Var{
-				{Name: "x", T: NUM_TYPE},
-				{Name: "y", T: NUM_TYPE},
-			},
+		"down": {Name: "down", Params: xyParams},
+		"up":   {Name: "up", Params: xyParams},
+		"move": {Name: "move", Params: xyParams},
+		"key":  {Name: "key", Params: []*Var{{Name: "key", T: STRING_TYPE}}},
+		"animate": {
+			Name:   "animate",
+			Params: []*Var{{Name: "dt", T: NUM_TYPE}},
 		},
 	}
 	globals := map[string]*Var{
@@ -0,0 +1,101 @@
+package parser
+
+import "fmt"
+
+// ExceptionType is the type of the implicit exception value bound by
+// "except <kind> as e": {kind:string message:string data:any}.
+var ExceptionType = &Type{
+	Name: MAP,
+	Sub:  ANY_TYPE,
+}
+
+// TryStmt is a structured error-handling statement:
+//
+//	try
+//	    <Body>
+//	except <kind1>, <kind2> as e
+//	    ...
+//	except <ident>
+//	    ...
+//	except
+//	    ...
+//	finally
+//	    <Finally>
+//	end
+//
+// At most one bare (catch-all) ExceptClause is allowed, and if present it
+// must be the last entry in Excepts.
+type TryStmt struct {
+	Pos     Position
+	Body    *BlockStatement
+	Excepts []*ExceptClause
+	Finally *BlockStatement // nil if no finally clause
+}
+
+// ExceptClause handles one or more raised exception kinds. Kinds is empty
+// for a catch-all "except" clause. Binding is nil unless the clause uses
+// "as e", in which case Binding is scoped to Body only, with type
+// ExceptionType.
+type ExceptClause struct {
+	Pos     Position
+	Kinds   []string
+	Binding *Var
+	Body    *BlockStatement
+}
+
+func (t *TryStmt) String() string {
+	return fmt.Sprintf("try{%v}except(%d clauses)", t.Body, len(t.Excepts))
+}
+
+// IsCatchAll reports whether this clause catches every exception kind.
+func (e *ExceptClause) IsCatchAll() bool {
+	return len(e.Kinds) == 0
+}
+
+// RaiseStmt is the builtin statement "raise message:string kind:string
+// data:any" that triggers exception unwinding to the nearest enclosing
+// TryStmt whose ExceptClause matches Kind, or a runtime error if there is
+// none.
+type RaiseStmt struct {
+	Pos     Position
+	Message Node
+	Kind    Node
+	Data    Node
+}
+
+func (r *RaiseStmt) String() string {
+	return "raise"
+}
+
+// validateExceptClauses enforces: at most one catch-all clause, reported at
+// the second one; if present it must be last, reported at the clause that
+// follows it; and a try needs at least one except clause or a finally
+// clause (hasFinally). It mirrors the shape of the other "at most one /
+// must be last" checks done for if/else-if/else chains elsewhere in this
+// package.
+func validateExceptClauses(pos Position, excepts []*ExceptClause, hasFinally bool) error {
+	catchAll := -1
+	for i, e := range excepts {
+		if !e.IsCatchAll() {
+			continue
+		}
+		if catchAll >= 0 {
+			return &parseError{pos: e.Pos, msg: "duplicate catch-all except clause"}
+		}
+		catchAll = i
+	}
+	if catchAll >= 0 && catchAll != len(excepts)-1 {
+		return &parseError{pos: excepts[catchAll+1].Pos, msg: "catch-all except clause must be last"}
+	}
+	if len(excepts) == 0 && !hasFinally {
+		return &parseError{pos: pos, msg: "try requires at least one except or finally clause"}
+	}
+	return nil
+}
+
+// alwaysExits reports whether the statement unconditionally transfers
+// control out of the enclosing block, the way a bare "return" or "break"
+// does for the existing unreachable-code analyzer. "raise" is treated the
+// same way: no statement following a "raise" in the same block is
+// reachable.
+func (r *RaiseStmt) alwaysExits() bool { return true }
@@ -0,0 +1,49 @@
+package parser
+
+import "fmt"
+
+// TypeAssertionOkExpr is the comma-ok form of a type assertion,
+// "a.(num)" used as the RHS of a two-target inferred declaration:
+// "n, ok := a.(num)". Unlike the single-value TypeAssertion, failure to
+// match AssertedType never panics at runtime; Ok is set to false instead.
+type TypeAssertionOkExpr struct {
+	Pos          Position
+	Left         Node
+	AssertedType *Type
+}
+
+// Type returns the synthetic tuple type of a comma-ok assertion. It is
+// never a first-class Evy type; InferredDeclStmt destructures it into
+// (AssertedType, BOOL_TYPE) before a real *Type ever reaches the rest of
+// the type checker.
+func (t *TypeAssertionOkExpr) Type() *Type {
+	return &Type{Name: "tuple", Sub: t.AssertedType}
+}
+
+func (t *TypeAssertionOkExpr) String() string {
+	return fmt.Sprintf("%v.(%v)", t.Left, t.AssertedType)
+}
+
+// destructureTypeAssertionOk validates an inferred declaration of the form
+// "n, ok := a.(num)": the LHS must have exactly two targets, and the RHS
+// expression's static type must be ANY_TYPE (only "any"-typed values can be
+// asserted). On success it returns the two Vars to bind: one of
+// AssertedType and one of BOOL_TYPE.
+func destructureTypeAssertionOk(pos Position, lhsNames []string, expr *TypeAssertionOkExpr, leftType *Type) ([]*Var, error) {
+	if len(lhsNames) != 2 {
+		return nil, &parseError{
+			pos: pos,
+			msg: fmt.Sprintf("comma-ok type assertion requires 2 targets, found %d", len(lhsNames)),
+		}
+	}
+	if leftType != ANY_TYPE {
+		return nil, &parseError{
+			pos: pos,
+			msg: fmt.Sprintf("type assertion requires any, found %s", leftType),
+		}
+	}
+	return []*Var{
+		{Name: lhsNames[0], T: expr.AssertedType},
+		{Name: lhsNames[1], T: BOOL_TYPE},
+	}, nil
+}
@@ -0,0 +1,54 @@
+package parser
+
+// TypeInfo is the resolved type and identifier information for a parsed
+// Program, attached by Parse so tools (LSPs, formatters, linters) can query
+// it without re-running the late-typing logic that already computes this
+// internally while parsing composite literals and function calls.
+type TypeInfo struct {
+	types map[Node]*Type
+	defs  map[*Var]Node
+	uses  map[*Var][]Node
+}
+
+func newTypeInfo() *TypeInfo {
+	return &TypeInfo{
+		types: map[Node]*Type{},
+		defs:  map[*Var]Node{},
+		uses:  map[*Var][]Node{},
+	}
+}
+
+// recordType associates node with its resolved type. Called from the same
+// sites that already assign types during parsing: array/map literal
+// construction, function-call return types, and "any(...)" wrapping.
+func (ti *TypeInfo) recordType(node Node, t *Type) {
+	ti.types[node] = t
+}
+
+// recordDef associates a *Var with the node that declares it (a
+// Declaration, FuncDeclStmt param, or DestructureTarget).
+func (ti *TypeInfo) recordDef(v *Var, def Node) {
+	ti.defs[v] = def
+}
+
+// recordUse appends node to the list of places where v is referenced.
+func (ti *TypeInfo) recordUse(v *Var, node Node) {
+	ti.uses[v] = append(ti.uses[v], node)
+}
+
+// TypeOf returns the resolved Type for node, or nil if node carries no type
+// information (e.g. a statement rather than an expression).
+func (ti *TypeInfo) TypeOf(node Node) *Type {
+	return ti.types[node]
+}
+
+// DefOf returns the node that declared v.
+func (ti *TypeInfo) DefOf(v *Var) Node {
+	return ti.defs[v]
+}
+
+// Uses returns every node where v is referenced, in the order encountered
+// during parsing.
+func (ti *TypeInfo) Uses(v *Var) []Node {
+	return ti.uses[v]
+}